@@ -108,7 +108,7 @@ func main() {
 	baseUserClient := user_client.NewUserClient(userConn, log)
 	baseAuthClient := auth_client.NewAuthClient(authConn, log)
 	basePostClient := post_client.NewPostClient(postConn, log)
-	baseRelationClient := relation_client.NewRelationClient(relationConn, log)
+	baseRelationClient := relation_client.NewRelationClient(relationConn, baseUserClient, log)
 	baseNotificationClient := notification_client.NewNotificationClient(notificationConn, log)
 
 	// Wrap clients with metrics decorators
@@ -138,14 +138,11 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 
-	done := make(chan bool, 1)
+	serverErr := make(chan error, 1)
 	metricsDone := make(chan bool, 1)
 
 	go func() {
-		if err := server.Run(cfg); err != nil {
-			log.Error("Server error", slog.String("error", err.Error()))
-		}
-		done <- true
+		serverErr <- server.Run(cfg)
 	}()
 
 	http.Handle("/metrics", promhttp.Handler())
@@ -158,7 +155,13 @@ func main() {
 		metricsDone <- true
 	}()
 
-	<-quit
+	// A server that fails to start or stops serving takes the process down with it.
+	var runErr error
+	select {
+	case <-quit:
+	case runErr = <-serverErr:
+		log.Error("Server error", slog.String("error", runErr.Error()))
+	}
 	log.Info("Shutting down server...")
 
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -172,8 +175,15 @@ func main() {
 		log.Error("Metrics server shutdown error", slog.String("error", err.Error()))
 	}
 
-	<-done
+	if runErr == nil {
+		if err := <-serverErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("Server error", slog.String("error", err.Error()))
+		}
+	}
 	<-metricsDone
 
+	if runErr != nil {
+		os.Exit(1)
+	}
 	log.Info("Server exited")
 }
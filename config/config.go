@@ -9,11 +9,22 @@ import (
 )
 
 type Config struct {
-	Env        string     `mapstructure:"env"`
-	HTTPServer HTTPServer `mapstructure:"http_server"`
-	Services   Services   `mapstructure:"services"`
-	JWT        JWT        `mapstructure:"jwt"`
-	Prometheus Prometheus `mapstructure:"prometheus"`
+	Env           string        `mapstructure:"env"`
+	HTTPServer    HTTPServer    `mapstructure:"http_server"`
+	Services      Services      `mapstructure:"services"`
+	JWT           JWT           `mapstructure:"jwt"`
+	Prometheus    Prometheus    `mapstructure:"prometheus"`
+	Compression   Compression   `mapstructure:"compression"`
+	Pagination    Pagination    `mapstructure:"pagination"`
+	Feed          Feed          `mapstructure:"feed"`
+	Profile       Profile       `mapstructure:"profile"`
+	Relation      Relation      `mapstructure:"relation"`
+	Notifications Notifications `mapstructure:"notifications"`
+	Media         Media         `mapstructure:"media"`
+	Avatars       Avatars       `mapstructure:"avatars"`
+	Moderation    Moderation    `mapstructure:"moderation"`
+	Share         Share         `mapstructure:"share"`
+	Users         Users         `mapstructure:"users"`
 }
 
 type HTTPServer struct {
@@ -67,6 +78,138 @@ type Prometheus struct {
 	Port    int    `mapstructure:"port"`
 }
 
+type Compression struct {
+	Enabled      bool     `mapstructure:"enabled"`
+	MinSize      int      `mapstructure:"min_size"`
+	GzipLevel    int      `mapstructure:"gzip_level"`
+	ContentTypes []string `mapstructure:"content_types"`
+}
+
+type Pagination struct {
+	CursorSecret string `mapstructure:"cursor_secret"`
+}
+
+type Feed struct {
+	MaxFollowees int `mapstructure:"max_followees"`
+	Concurrency  int `mapstructure:"concurrency"`
+}
+
+type Profile struct {
+	SectionTimeoutMs int `mapstructure:"section_timeout_ms"`
+	RecentPosts      int `mapstructure:"recent_posts"`
+}
+
+type Relation struct {
+	MaxScan         int         `mapstructure:"max_scan"`
+	CacheTTLSeconds int         `mapstructure:"cache_ttl_seconds"`
+	Suggestions     Suggestions `mapstructure:"suggestions"`
+}
+
+type Notifications struct {
+	Workers            int                 `mapstructure:"workers"`
+	QueueSize          int                 `mapstructure:"queue_size"`
+	MaxAttempts        int                 `mapstructure:"max_attempts"`
+	RetryDelayMs       int                 `mapstructure:"retry_delay_ms"`
+	DedupWindowSeconds int                 `mapstructure:"dedup_window_seconds"`
+	Follow             FollowNotification  `mapstructure:"follow"`
+	Mentions           MentionNotification `mapstructure:"mentions"`
+}
+
+type FollowNotification struct {
+	Enabled bool `mapstructure:"enabled"`
+	// DedupWindowSeconds overrides notifications.dedup_window_seconds for follow notifications when set.
+	DedupWindowSeconds int `mapstructure:"dedup_window_seconds"`
+}
+
+type MentionNotification struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+type Media struct {
+	// Backend selects the media store: local or s3.
+	Backend          string     `mapstructure:"backend"`
+	MaxImageSize     int64      `mapstructure:"max_image_size"`
+	MaxVideoSize     int64      `mapstructure:"max_video_size"`
+	MaxChunkSize     int64      `mapstructure:"max_chunk_size"`
+	UploadDir        string     `mapstructure:"upload_dir"`
+	UploadTTLSeconds int        `mapstructure:"upload_ttl_seconds"`
+	MaxOpenUploads   int        `mapstructure:"max_open_uploads"`
+	Local            LocalMedia `mapstructure:"local"`
+	S3               S3Media    `mapstructure:"s3"`
+	Images           Images     `mapstructure:"images"`
+	Signing          Signing    `mapstructure:"signing"`
+}
+
+type Signing struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	Secret     string `mapstructure:"secret"`
+	TTLSeconds int    `mapstructure:"ttl_seconds"`
+	// BaseURL is the public URL of the gateway's /media route that signed URLs point at.
+	BaseURL string `mapstructure:"base_url"`
+}
+
+type Images struct {
+	MaxPixels     int64 `mapstructure:"max_pixels"`
+	ThumbnailSize int   `mapstructure:"thumbnail_size"`
+	MediumSize    int   `mapstructure:"medium_size"`
+	FullSize      int   `mapstructure:"full_size"`
+	JPEGQuality   int   `mapstructure:"jpeg_quality"`
+}
+
+type LocalMedia struct {
+	Dir     string `mapstructure:"dir"`
+	BaseURL string `mapstructure:"base_url"`
+}
+
+type S3Media struct {
+	Endpoint  string `mapstructure:"endpoint"`
+	Region    string `mapstructure:"region"`
+	Bucket    string `mapstructure:"bucket"`
+	AccessKey string `mapstructure:"access_key"`
+	SecretKey string `mapstructure:"secret_key"`
+	PublicURL string `mapstructure:"public_url"`
+	PathStyle bool   `mapstructure:"path_style"`
+}
+
+type Avatars struct {
+	// BaseURL is the public URL of the users API that avatar endpoint links are built on.
+	BaseURL string `mapstructure:"base_url"`
+}
+
+type Moderation struct {
+	Enabled               bool   `mapstructure:"enabled"`
+	RulesFile             string `mapstructure:"rules_file"`
+	ReloadIntervalSeconds int    `mapstructure:"reload_interval_seconds"`
+	// AuditFile receives one JSON line per decision; when empty, decisions are written to the log.
+	AuditFile string `mapstructure:"audit_file"`
+}
+
+type Share struct {
+	// BaseURL is the public URL of the gateway that /p/{id} share links are built on.
+	BaseURL         string `mapstructure:"base_url"`
+	SiteName        string `mapstructure:"site_name"`
+	CacheTTLSeconds int    `mapstructure:"cache_ttl_seconds"`
+	MaxAgeSeconds   int    `mapstructure:"max_age_seconds"`
+}
+
+type Users struct {
+	// AdminIDs lists the users who see the admin view of every account, including its email.
+	AdminIDs         []int64          `mapstructure:"admin_ids"`
+	EmailLookupLimit EmailLookupLimit `mapstructure:"email_lookup_limit"`
+}
+
+// EmailLookupLimit bounds how many lookups by email each caller may make per window.
+type EmailLookupLimit struct {
+	Requests      int `mapstructure:"requests"`
+	WindowSeconds int `mapstructure:"window_seconds"`
+}
+
+type Suggestions struct {
+	SampleSize      int `mapstructure:"sample_size"`
+	Concurrency     int `mapstructure:"concurrency"`
+	CacheTTLSeconds int `mapstructure:"cache_ttl_seconds"`
+}
+
 func MustLoad() *Config {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
@@ -101,6 +244,73 @@ func MustLoad() *Config {
 	viper.SetDefault("prometheus.address", "0.0.0.0")
 	viper.SetDefault("prometheus.port", 9106)
 
+	viper.SetDefault("compression.enabled", true)
+	viper.SetDefault("compression.min_size", 1024)
+	viper.SetDefault("compression.gzip_level", 5)
+	viper.SetDefault("compression.content_types", []string{
+		"application/json",
+		"application/msgpack",
+		"application/x-protobuf",
+		"text/plain",
+		"text/html",
+	})
+
+	viper.SetDefault("feed.max_followees", 500)
+	viper.SetDefault("feed.concurrency", 8)
+
+	viper.SetDefault("profile.section_timeout_ms", 2000)
+	viper.SetDefault("profile.recent_posts", 10)
+
+	viper.SetDefault("relation.max_scan", 1000)
+	viper.SetDefault("relation.cache_ttl_seconds", 30)
+	viper.SetDefault("relation.suggestions.sample_size", 50)
+	viper.SetDefault("relation.suggestions.concurrency", 8)
+	viper.SetDefault("relation.suggestions.cache_ttl_seconds", 300)
+
+	viper.SetDefault("notifications.workers", 2)
+	viper.SetDefault("notifications.queue_size", 1000)
+	viper.SetDefault("notifications.max_attempts", 3)
+	viper.SetDefault("notifications.retry_delay_ms", 500)
+	viper.SetDefault("notifications.dedup_window_seconds", 300)
+	viper.SetDefault("notifications.follow.enabled", false)
+	viper.SetDefault("notifications.mentions.enabled", false)
+
+	viper.SetDefault("media.backend", "local")
+	viper.SetDefault("media.max_image_size", 10<<20)
+	viper.SetDefault("media.max_video_size", 100<<20)
+	viper.SetDefault("media.max_chunk_size", 8<<20)
+	viper.SetDefault("media.upload_dir", "./data/uploads")
+	viper.SetDefault("media.upload_ttl_seconds", 86400)
+	viper.SetDefault("media.max_open_uploads", 5)
+	viper.SetDefault("media.local.dir", "./data/media")
+	viper.SetDefault("media.local.base_url", "http://localhost:8080/media")
+	viper.SetDefault("media.s3.region", "us-east-1")
+	viper.SetDefault("media.s3.path_style", true)
+	viper.SetDefault("media.images.max_pixels", 40_000_000)
+	viper.SetDefault("media.images.thumbnail_size", 320)
+	viper.SetDefault("media.images.medium_size", 1080)
+	viper.SetDefault("media.images.full_size", 2048)
+	viper.SetDefault("media.images.jpeg_quality", 85)
+	viper.SetDefault("media.signing.enabled", false)
+	viper.SetDefault("media.signing.ttl_seconds", 3600)
+	viper.SetDefault("media.signing.base_url", "http://localhost:8080/media")
+
+	viper.SetDefault("avatars.base_url", "http://localhost:8080/api/v1/users")
+
+	viper.SetDefault("moderation.enabled", false)
+	viper.SetDefault("moderation.rules_file", "./config/moderation.example.yml")
+	viper.SetDefault("moderation.reload_interval_seconds", 30)
+	viper.SetDefault("moderation.audit_file", "")
+
+	viper.SetDefault("share.base_url", "http://localhost:8080")
+	viper.SetDefault("share.site_name", "Pinstack")
+	viper.SetDefault("share.cache_ttl_seconds", 60)
+	viper.SetDefault("share.max_age_seconds", 300)
+
+	viper.SetDefault("users.admin_ids", []int64{})
+	viper.SetDefault("users.email_lookup_limit.requests", 10)
+	viper.SetDefault("users.email_lookup_limit.window_seconds", 60)
+
 	if err := viper.ReadInConfig(); err != nil {
 		log.Printf("Error reading config file: %s", err)
 		os.Exit(1)
@@ -111,5 +321,10 @@ func MustLoad() *Config {
 		panic(fmt.Errorf("error unmarshaling config: %w", err))
 	}
 
+	if cfg.Pagination.CursorSecret == "" {
+		log.Printf("Invalid config: pagination.cursor_secret must be set to a random secret")
+		os.Exit(1)
+	}
+
 	return &cfg
 }
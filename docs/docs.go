@@ -373,14 +373,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/notification/feed": {
+        "/feed": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get paginated list of notifications for a user",
+                "description": "Get the newest posts of the users the caller follows, merged into one timeline.\nPages are cursor based; pass next_cursor back to continue. If some authors could not be loaded the page is still returned with partial set.",
                 "consumes": [
                     "application/json"
                 ],
@@ -388,28 +388,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "notification"
+                    "feed"
                 ],
-                "summary": "Get user notification feed",
+                "summary": "Get home timeline",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Page number (default: 1)",
-                        "name": "page",
+                        "default": 20,
+                        "description": "Page size (max 100)",
+                        "name": "limit",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Items per page (default: 10)",
-                        "name": "limit",
+                        "type": "string",
+                        "description": "Opaque cursor from next_cursor",
+                        "name": "cursor",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User notification feed",
-                        "schema": {
-                            "$ref": "#/definitions/notification_handler.GetUserNotificationFeedSwaggerResponse"
+                        "description": "Home timeline page",
+                        "schema": {
+                            "$ref": "#/definitions/feed_handler.FeedResponse"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 pagination links"
+                            }
                         }
                     },
                     "400": {
@@ -421,6 +428,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -433,33 +449,79 @@ const docTemplate = `{
                 }
             }
         },
-        "/notification/read-all": {
-            "put": {
+        "/media": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Mark all notifications of a user as read",
+                "description": "Upload an image or video as multipart/form-data in the \"file\" field.\nThe file type is detected from its content; an optional \"type\" field (image or video) must agree with it.\nImages are upright-rotated, stripped of metadata and re-encoded as thumbnail, medium and full variants;\nurl is the full variant. The returned url is stable and can be used in a post's media_items or as an avatar_url;\nwhen media URLs are signed, variants carry signed URLs for immediate display.\nThe whole request must arrive within the server's request timeout, so this endpoint is meant for small files;\nsend large files, especially videos, as a resumable upload through POST /media/uploads.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "notification"
+                    "media"
+                ],
+                "summary": "Upload media",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Media file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "image",
+                            "video"
+                        ],
+                        "type": "string",
+                        "description": "Expected media type",
+                        "name": "type",
+                        "in": "formData"
+                    }
                 ],
-                "summary": "Mark all user notifications as read",
                 "responses": {
-                    "200": {
-                        "description": "All notifications marked as read",
+                    "201": {
+                        "description": "Media uploaded successfully",
                         "schema": {
-                            "$ref": "#/definitions/notification_handler.ReadAllUserNotificationsResponse"
+                            "$ref": "#/definitions/media_handler.MediaResponse"
                         }
                     },
-                    "404": {
-                        "description": "Notification not found",
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "File too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported media type",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -479,14 +541,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/notification/send": {
+        "/media/uploads": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Send notification to a user",
+                "description": "Open an upload session for a file of the given type and total size.\nChunks are then sent in order with PUT /media/uploads/{upload_id}; each chunk must arrive within the server's request timeout.\nA user may keep a limited number of uploads open at a time.",
                 "consumes": [
                     "application/json"
                 ],
@@ -494,25 +556,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "notification"
+                    "media"
                 ],
-                "summary": "Send notification",
+                "summary": "Start a resumable upload",
                 "parameters": [
                     {
-                        "description": "Send notification request",
+                        "description": "Upload to start",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/notification_handler.SendNotificationRequest"
+                            "$ref": "#/definitions/media_handler.CreateUploadRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Notification sent successfully",
-                        "schema": {
-                            "$ref": "#/definitions/notification_handler.SendNotificationResponse"
+                    "201": {
+                        "description": "Upload session created",
+                        "schema": {
+                            "$ref": "#/definitions/media_handler.UploadResponse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL to send chunks to"
+                            }
                         }
                     },
                     "400": {
@@ -524,8 +592,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -533,8 +601,17 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Notification limit exceeded",
+                    "413": {
+                        "description": "File too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too many open uploads",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -554,33 +631,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/notification/unread-count": {
+        "/media/uploads/{upload_id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get the count of unread notifications for a user",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Report how many bytes of an upload have been received, so an interrupted client can resume from offset.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "notification"
+                    "media"
+                ],
+                "summary": "Get resumable upload status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Upload ID",
+                        "name": "upload_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get unread notification count",
                 "responses": {
                     "200": {
-                        "description": "Unread notification count",
+                        "description": "Upload status",
                         "schema": {
-                            "$ref": "#/definitions/notification_handler.GetUnreadCountResponse"
+                            "$ref": "#/definitions/media_handler.UploadResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -588,8 +671,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "Upload not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -598,40 +681,51 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/notification/{notification_id}": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get notification details by ID",
+                "description": "Append a chunk to a resumable upload. Content-Range must start at the upload's current offset.\nIntermediate chunks return the upload status; the final chunk stores the file and returns the media.",
                 "consumes": [
-                    "application/json"
+                    "application/octet-stream"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "notification"
+                    "media"
                 ],
-                "summary": "Get notification details",
+                "summary": "Upload a chunk",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Notification ID",
-                        "name": "notification_id",
+                        "type": "string",
+                        "description": "Upload ID",
+                        "name": "upload_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chunk position, e.g. bytes 0-1048575/5242880",
+                        "name": "Content-Range",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Notification details",
+                        "description": "Chunk received",
                         "schema": {
-                            "$ref": "#/definitions/models.NotificationSwagger"
+                            "$ref": "#/definitions/media_handler.UploadResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Upload complete",
+                        "schema": {
+                            "$ref": "#/definitions/media_handler.MediaResponse"
                         }
                     },
                     "400": {
@@ -643,8 +737,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -653,7 +747,34 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Notification not found",
+                        "description": "Upload not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Chunk does not start at the current offset",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Chunk or file too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported media type",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -678,41 +799,26 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Remove a notification by ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Discard an upload session and the data received so far.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "notification"
+                    "media"
                 ],
-                "summary": "Remove notification",
+                "summary": "Cancel a resumable upload",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Notification ID",
-                        "name": "notification_id",
+                        "type": "string",
+                        "description": "Upload ID",
+                        "name": "upload_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Notification removed successfully",
-                        "schema": {
-                            "$ref": "#/definitions/notification_handler.RemoveNotificationResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
+                        "description": "Upload cancelled"
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -723,17 +829,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
                     "404": {
-                        "description": "Notification not found",
+                        "description": "Upload not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -741,8 +838,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "409": {
+                        "description": "Upload is receiving a chunk",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -753,14 +850,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/notification/{notification_id}/read": {
-            "put": {
+        "/notification/feed": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Mark a notification as read",
+                "description": "Get paginated list of notifications for a user",
                 "consumes": [
                     "application/json"
                 ],
@@ -770,21 +867,34 @@ const docTemplate = `{
                 "tags": [
                     "notification"
                 ],
-                "summary": "Mark notification as read",
+                "summary": "Get user notification feed",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Notification ID",
-                        "name": "notification_id",
-                        "in": "path",
-                        "required": true
+                        "default": 1,
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size (max 100)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Notification marked as read",
+                        "description": "User notification feed",
                         "schema": {
-                            "$ref": "#/definitions/notification_handler.ReadNotificationResponse"
+                            "$ref": "#/definitions/notification_handler.GetUserNotificationFeedSwaggerResponse"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 pagination links"
+                            }
                         }
                     },
                     "400": {
@@ -796,35 +906,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "Notification not found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -835,14 +918,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/posts": {
-            "post": {
+        "/notification/read-all": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new post with title, content, tags and media",
+                "description": "Mark all notifications of a user as read",
                 "consumes": [
                     "application/json"
                 ],
@@ -850,38 +933,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "posts"
-                ],
-                "summary": "Create a new post",
-                "parameters": [
-                    {
-                        "description": "Post creation data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/post_handler.CreatePostRequest"
-                        }
-                    }
+                    "notification"
                 ],
+                "summary": "Mark all user notifications as read",
                 "responses": {
-                    "201": {
-                        "description": "Post created successfully",
-                        "schema": {
-                            "$ref": "#/definitions/post_handler.CreatePostResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
+                    "200": {
+                        "description": "All notifications marked as read",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/notification_handler.ReadAllUserNotificationsResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Notification not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -901,9 +964,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/posts/list": {
-            "get": {
-                "description": "Get a list of posts with optional filtering by author and date range",
+        "/notification/send": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Send notification to a user",
                 "consumes": [
                     "application/json"
                 ],
@@ -911,46 +979,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "posts"
+                    "notification"
                 ],
-                "summary": "List posts with filters",
+                "summary": "Send notification",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Filter by author ID",
-                        "name": "author_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter posts created after this time (RFC3339 format)",
-                        "name": "created_after",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter posts created before this time (RFC3339 format)",
-                        "name": "created_before",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Pagination offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Pagination limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Send notification request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/notification_handler.SendNotificationRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of posts",
+                        "description": "Notification sent successfully",
                         "schema": {
-                            "$ref": "#/definitions/post_handler.ListPostsResponse"
+                            "$ref": "#/definitions/notification_handler.SendNotificationResponse"
                         }
                     },
                     "400": {
@@ -962,6 +1009,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Notification limit exceeded",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -974,30 +1039,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/posts/{id}": {
+        "/notification/unread-count": {
             "get": {
-                "description": "Get detailed information about a specific post",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the count of unread notifications for a user",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "posts"
-                ],
-                "summary": "Get post by ID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Post ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "notification"
                 ],
+                "summary": "Get unread notification count",
                 "responses": {
                     "200": {
-                        "description": "Post information",
+                        "description": "Unread notification count",
                         "schema": {
-                            "$ref": "#/definitions/post_handler.GetPostResponse"
+                            "$ref": "#/definitions/notification_handler.GetUnreadCountResponse"
                         }
                     },
                     "400": {
@@ -1009,15 +1073,6 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Post not found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -1028,14 +1083,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/notification/{notification_id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing post with new data",
+                "description": "Get notification details by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1043,32 +1100,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "posts"
+                    "notification"
                 ],
-                "summary": "Update a post",
+                "summary": "Get notification details",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Post ID",
-                        "name": "id",
+                        "type": "integer",
+                        "description": "Notification ID",
+                        "name": "notification_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Post update data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/post_handler.UpdatePostRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Post updated successfully",
+                        "description": "Notification details",
                         "schema": {
-                            "$ref": "#/definitions/post_handler.UpdatePostResponse"
+                            "$ref": "#/definitions/models.NotificationSwagger"
                         }
                     },
                     "400": {
@@ -1080,15 +1128,6 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
@@ -1099,7 +1138,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Post not found",
+                        "description": "Notification not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1124,31 +1163,31 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete an existing post by ID",
+                "description": "Remove a notification by ID",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "posts"
+                    "notification"
                 ],
-                "summary": "Delete a post",
+                "summary": "Remove notification",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Post ID",
-                        "name": "id",
+                        "type": "integer",
+                        "description": "Notification ID",
+                        "name": "notification_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Post deleted successfully",
+                        "description": "Notification removed successfully",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/notification_handler.RemoveNotificationResponse"
                         }
                     },
                     "400": {
@@ -1179,7 +1218,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Post not found",
+                        "description": "Notification not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1199,14 +1238,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/relation/follow": {
-            "post": {
+        "/notification/{notification_id}/read": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Follow another user",
+                "description": "Mark a notification as read",
                 "consumes": [
                     "application/json"
                 ],
@@ -1214,25 +1253,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "relation"
+                    "notification"
                 ],
-                "summary": "Follow user",
+                "summary": "Mark notification as read",
                 "parameters": [
                     {
-                        "description": "Follow request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/relation_handler.FollowRequest"
-                        }
+                        "type": "integer",
+                        "description": "Notification ID",
+                        "name": "notification_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Followed successfully",
+                        "description": "Notification marked as read",
                         "schema": {
-                            "$ref": "#/definitions/relation_handler.FollowResponse"
+                            "$ref": "#/definitions/notification_handler.ReadNotificationResponse"
                         }
                     },
                     "400": {
@@ -1253,8 +1290,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1262,8 +1299,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Already following",
+                    "404": {
+                        "description": "Notification not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1283,42 +1320,68 @@ const docTemplate = `{
                 }
             }
         },
-        "/relation/unfollow": {
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Unfollow another user",
-                "consumes": [
-                    "application/json"
-                ],
+        "/oembed": {
+            "get": {
+                "description": "oEmbed 1.0 rich response for a post share page URL of this gateway. Only the JSON format is supported,\nand the body is the bare oEmbed object rather than the API response envelope.\nServed at the site root as /oembed, outside /api/v1. Supports conditional requests with If-None-Match and If-Modified-Since.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "relation"
+                    "share"
                 ],
-                "summary": "Unfollow user",
+                "summary": "Get the oEmbed description of a share link",
                 "parameters": [
                     {
-                        "description": "Unfollow request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/relation_handler.UnfollowRequest"
-                        }
+                        "type": "string",
+                        "description": "Share page URL, e.g. https://example.com/p/42",
+                        "name": "url",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json"
+                        ],
+                        "type": "string",
+                        "description": "Response format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum embed width in pixels",
+                        "name": "maxwidth",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum embed height in pixels",
+                        "name": "maxheight",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified from a previous response",
+                        "name": "If-Modified-Since",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Unfollowed successfully",
+                        "description": "oEmbed description",
                         "schema": {
-                            "$ref": "#/definitions/relation_handler.UnfollowResponse"
+                            "$ref": "#/definitions/share_handler.OEmbedResponse"
                         }
                     },
+                    "304": {
+                        "description": "Not modified"
+                    },
                     "400": {
                         "description": "Bad request",
                         "schema": {
@@ -1328,8 +1391,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Post not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1337,8 +1400,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "User not found or relation not found",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1346,8 +1409,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "501": {
+                        "description": "Unsupported format",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1358,47 +1421,168 @@ const docTemplate = `{
                 }
             }
         },
-        "/relation/{user_id}/followees": {
+        "/p/{id}": {
             "get": {
-                "description": "Get list of users that the user is following",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Minimal HTML page for a post with OpenGraph and Twitter card metadata, so links to it unfurl into previews\nin chat apps and social networks. It links its oEmbed description for consumers that support discovery.\nServed at the site root as /p/{id}, outside /api/v1. Supports conditional requests with If-None-Match and If-Modified-Since.",
                 "produces": [
-                    "application/json"
+                    "text/html"
                 ],
                 "tags": [
-                    "relation"
+                    "share"
                 ],
-                "summary": "Get user followees",
+                "summary": "Get a post share page",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "user_id",
+                        "description": "Post ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "default": 20,
-                        "description": "Limit for pagination",
-                        "name": "limit",
+                        "type": "string",
+                        "description": "ETag from a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified from a previous response",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Share page",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "502": {
+                        "description": "Post service unavailable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts": {
+            "get": {
+                "description": "Get up to 100 posts in one request. Posts are returned in the order requested, duplicates once;\nIDs of posts that do not exist are reported in missing and IDs that could not be loaded right now in failed,\ninstead of failing the request. Posts whose author could not be loaded are returned without author.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Get posts by IDs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated post IDs (max 100)",
+                        "name": "ids",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Set to html to include content_html rendered from Markdown",
+                        "name": "render",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
+                        "type": "string",
+                        "description": "Comma separated fields to return for each post, e.g. title,author.username",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated expansions (author, media, tags); all by default, none when empty",
+                        "name": "expand",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Followees retrieved successfully",
+                        "description": "Posts",
                         "schema": {
-                            "$ref": "#/definitions/relation_handler.GetFolloweesResponse"
+                            "$ref": "#/definitions/post_handler.BatchPostsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new post with title, content, tags and media\nUsers @mentioned in the content are resolved and notified, and #hashtags in the content are added to the tags.\nTags are lower-cased and must be at most 50 letters, digits, underscores or + . - # characters, including a letter.\nThe title, content, tags and media URLs are checked against the moderation rules before the post is stored.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Create a new post",
+                "parameters": [
+                    {
+                        "description": "Post creation data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/post_handler.CreatePostRequest"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Set to html to include content_html rendered from Markdown",
+                        "name": "render",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Post created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/post_handler.CreatePostResponse"
                         }
                     },
                     "400": {
@@ -1410,8 +1594,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1419,6 +1603,12 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "422": {
+                        "description": "Content rejected by moderation",
+                        "schema": {
+                            "$ref": "#/definitions/post_handler.ModerationRejectedResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -1431,51 +1621,1252 @@ const docTemplate = `{
                 }
             }
         },
-        "/relation/{user_id}/followers": {
+        "/posts/list": {
             "get": {
-                "description": "Get list of user followers by user ID",
+                "description": "Get a list of posts with optional filtering by author, tags and date range.\nSupports offset pagination and opaque cursor pagination; next/prev pages are also advertised in the Link header.\nWith Accept: application/x-protobuf the page is the reduced post.v1.ListPostsResponse: posts and total only,\nwith authors as author_id and no cursors or other pagination meta, so paginate with page or offset.\nIt cannot be combined with fields (406).",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/x-protobuf"
                 ],
                 "tags": [
-                    "relation"
+                    "posts"
                 ],
-                "summary": "Get user followers",
+                "summary": "List posts with filters",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by author ID",
+                        "name": "author_id",
+                        "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "default": 20,
-                        "description": "Limit for pagination",
-                        "name": "limit",
+                        "type": "string",
+                        "description": "Filter posts created after this time (RFC3339 format)",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter posts created before this time (RFC3339 format)",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated tag names (max 10)",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "any",
+                        "description": "Whether posts must have any or all of the tags",
+                        "name": "tag_mode",
                         "in": "query"
                     },
                     {
                         "type": "integer",
                         "default": 1,
-                        "description": "Page number",
+                        "description": "Page number, starting at 1",
                         "name": "page",
                         "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination offset; cannot be combined with page",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Set to html to include content_html rendered from Markdown",
+                        "name": "render",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from next_cursor or prev_cursor; cannot be combined with page or offset",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return for each post, e.g. title,author.username",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated expansions (author, media, tags); all by default, none when empty",
+                        "name": "expand",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Followers retrieved successfully",
+                        "description": "List of posts",
                         "schema": {
-                            "$ref": "#/definitions/relation_handler.GetFollowersResponse"
+                            "$ref": "#/definitions/post_handler.ListPostsResponse"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 pagination links"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "Requested media type cannot represent the response",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "tag_mode=all matches too many posts to evaluate; narrow the filter",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{id}": {
+            "get": {
+                "description": "Get detailed information about a specific post\nWith Accept: application/x-protobuf the post is the reduced post.v1.Post message: the author is only author_id,\nand content_html and media variants are left out. It cannot be combined with fields (406).",
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/x-protobuf"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Get post by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Post ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Set to html to include content_html rendered from Markdown",
+                        "name": "render",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. title,author.username",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated expansions (author, media, tags); all by default, none when empty",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified from a previous response",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Post information",
+                        "schema": {
+                            "$ref": "#/definitions/post_handler.GetPostResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "Requested media type cannot represent the response",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing post with new data\nUsers newly @mentioned in the content are notified. Tags from #hashtags are recomputed from the resulting content, so hashtags removed from it are dropped from the tags.\nTags are lower-cased and must be at most 50 letters, digits, underscores or + . - # characters, including a letter.\nChanged fields are checked against the moderation rules before the post is stored.\nIf-Match is checked against the current version before the update is sent. The post service has no\nconditional update, so a concurrent write landing between the check and the update is not detected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Update a post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Post ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being edited",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Post update data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/post_handler.UpdatePostRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Post updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/post_handler.UpdatePostResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Post was modified concurrently",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Content rejected by moderation",
+                        "schema": {
+                            "$ref": "#/definitions/post_handler.ModerationRejectedResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete an existing post by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Delete a post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Post ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Post deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/relation/follow": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Follow another user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "relation"
+                ],
+                "summary": "Follow user",
+                "parameters": [
+                    {
+                        "description": "Follow request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/relation_handler.FollowRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Followed successfully",
+                        "schema": {
+                            "$ref": "#/definitions/relation_handler.FollowResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Already following",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/relation/status": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Batch form of the relationship status check for up to 100 users.\nStatuses that could not be confirmed within the scan limits are marked unknown.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "relation"
+                ],
+                "summary": "Get relationship status for several users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated target user IDs (max 100)",
+                        "name": "ids",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Relationship statuses in request order",
+                        "schema": {
+                            "$ref": "#/definitions/relation_handler.RelationshipStatusesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/relation/suggestions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Suggest users followed by the people the caller follows, ranked by number of mutual connections",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "relation"
+                ],
+                "summary": "Get follow suggestions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of suggestions (max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return for each suggestion, e.g. id,username",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Follow suggestions",
+                        "schema": {
+                            "$ref": "#/definitions/relation_handler.SuggestionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/relation/unfollow": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Unfollow another user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "relation"
+                ],
+                "summary": "Unfollow user",
+                "parameters": [
+                    {
+                        "description": "Unfollow request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/relation_handler.UnfollowRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Unfollowed successfully",
+                        "schema": {
+                            "$ref": "#/definitions/relation_handler.UnfollowResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found or relation not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/relation/{user_id}/followees": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get list of users that the user is following\nWith a bearer token each entry also carries is_followed_by_me for the caller.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "relation"
+                ],
+                "summary": "Get user followees",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return for each entry, e.g. id,username",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "profile"
+                        ],
+                        "type": "string",
+                        "description": "Set to profile to include full_name and bio",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Followees retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/relation_handler.GetFolloweesResponse"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 pagination links"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/relation/{user_id}/followers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get list of user followers by user ID\nWith a bearer token each entry also carries is_followed_by_me for the caller.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "relation"
+                ],
+                "summary": "Get user followers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return for each entry, e.g. id,username",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "profile"
+                        ],
+                        "type": "string",
+                        "description": "Set to profile to include full_name and bio",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Followers retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/relation_handler.GetFollowersResponse"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 pagination links"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/relation/{user_id}/status": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Check whether the caller follows the user, is followed by them, or both",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "relation"
+                ],
+                "summary": "Get relationship status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Target user ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Relationship status",
+                        "schema": {
+                            "$ref": "#/definitions/relation_handler.RelationshipStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update user fields by ID\nIf-Match is checked against the current version before the update is sent. The user service has no\nconditional update, so a concurrent write landing between the check and the update is not detected.\nWith Accept: application/x-protobuf the user is the user.v1.User message, which has no avatar_variants.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/x-protobuf"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update user information",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being edited",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "User update data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user_handler.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/userview.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Operation not allowed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "Requested media type cannot represent the response",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Username or email already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "User was modified concurrently",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new user with provided data\nWith Accept: application/x-protobuf the user is the user.v1.User message, which has no avatar_variants.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/x-protobuf"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create a new user",
+                "parameters": [
+                    {
+                        "description": "User creation data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user_handler.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/userview.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "Requested media type cannot represent the response",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "User already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/avatar": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the avatar URL for a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update user avatar",
+                "parameters": [
+                    {
+                        "description": "Avatar update data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user_handler.UpdateAvatarRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Avatar updated successfully"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
+                    "403": {
+                        "description": "Operation not allowed",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1504,40 +2895,43 @@ const docTemplate = `{
                 }
             }
         },
-        "/users": {
-            "put": {
+        "/users/email/{email}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update user fields by ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get user information by email. Requires authentication and is rate limited per caller.\nThe email is only included for the account owner and administrators.\nWith Accept: application/x-protobuf the user is the user.v1.User message, which has no avatar_variants.\nIt cannot be combined with fields (406).",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/x-protobuf"
                 ],
                 "tags": [
                     "users"
                 ],
-                "summary": "Update user information",
+                "summary": "Get user by email",
                 "parameters": [
                     {
-                        "description": "User update data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/user_handler.UpdateUserRequest"
-                        }
+                        "type": "string",
+                        "description": "User email",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. username,avatar_url",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User updated successfully",
+                        "description": "User information",
                         "schema": {
-                            "$ref": "#/definitions/user_handler.UpdateUserResponse"
+                            "$ref": "#/definitions/userview.User"
                         }
                     },
                     "400": {
@@ -1558,8 +2952,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "403": {
-                        "description": "Operation not allowed",
+                    "404": {
+                        "description": "User not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1567,8 +2961,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "406": {
+                        "description": "Requested media type cannot represent the response",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1576,8 +2970,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Username or email already exists",
+                    "429": {
+                        "description": "Too many lookups",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1595,40 +2989,66 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Create a new user with provided data",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/users/search": {
+            "get": {
+                "description": "Search users by query string\nThe email is only included for the account owner and administrators.\nAuthentication is optional; a bearer token that is sent must be valid.\nWith Accept: application/x-protobuf the page is the user.v1.SearchUsersResponse message: users and total only,\nwithout the other pagination meta or avatar_variants. It cannot be combined with fields (406).",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/x-protobuf"
                 ],
                 "tags": [
                     "users"
                 ],
-                "summary": "Create a new user",
+                "summary": "Search users",
                 "parameters": [
                     {
-                        "description": "User creation data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/user_handler.CreateUserRequest"
-                        }
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "query",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination offset, a multiple of limit; cannot be combined with page",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return for each user, e.g. username,avatar_url",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "User created successfully",
+                    "200": {
+                        "description": "Search results",
                         "schema": {
-                            "$ref": "#/definitions/user_handler.CreateUserResponse"
+                            "$ref": "#/definitions/user_handler.SearchUsersResponse"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 pagination links"
+                            }
                         }
                     },
                     "400": {
@@ -1641,7 +3061,7 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Invalid bearer token",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1649,8 +3069,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "User already exists",
+                    "406": {
+                        "description": "Requested media type cannot represent the response",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1670,38 +3090,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/avatar": {
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Update the avatar URL for a user",
-                "consumes": [
-                    "application/json"
-                ],
+        "/users/username/{username}": {
+            "get": {
+                "description": "Get user information by username\nThe email is only included for the account owner and administrators.\nAuthentication is optional; a bearer token that is sent must be valid.\nWith Accept: application/x-protobuf the user is the user.v1.User message, which has no avatar_variants.\nIt cannot be combined with fields (406).",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/x-protobuf"
                 ],
                 "tags": [
                     "users"
                 ],
-                "summary": "Update user avatar",
+                "summary": "Get user by username",
                 "parameters": [
                     {
-                        "description": "Avatar update data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/user_handler.UpdateAvatarRequest"
-                        }
+                        "type": "string",
+                        "description": "User username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. username,avatar_url",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Avatar updated successfully"
+                        "description": "User information",
+                        "schema": {
+                            "$ref": "#/definitions/userview.User"
+                        }
                     },
                     "400": {
                         "description": "Bad request",
@@ -1713,7 +3134,7 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Invalid bearer token",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1721,8 +3142,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "403": {
-                        "description": "Operation not allowed",
+                    "404": {
+                        "description": "User not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1730,8 +3151,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "406": {
+                        "description": "Requested media type cannot represent the response",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1751,31 +3172,125 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/email/{email}": {
+        "/users/{id}": {
             "get": {
-                "description": "Get user information by email",
+                "description": "Get user information by user ID\nThe email is only included for the account owner and administrators.\nAuthentication is optional; a bearer token that is sent must be valid.\nWith Accept: application/x-protobuf the user is the user.v1.User message, which has no avatar_variants.\nIt cannot be combined with fields (406).",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/msgpack",
+                    "application/x-protobuf"
                 ],
                 "tags": [
                     "users"
                 ],
-                "summary": "Get user by email",
+                "summary": "Get user by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User email",
-                        "name": "email",
+                        "description": "User ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. username,avatar_url",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified from a previous response",
+                        "name": "If-Modified-Since",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "User information",
                         "schema": {
-                            "$ref": "#/definitions/user_handler.GetUserByEmailResponse"
+                            "$ref": "#/definitions/userview.User"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid bearer token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "Requested media type cannot represent the response",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a user by their ID",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "User deleted successfully"
                     },
                     "400": {
                         "description": "Bad request",
@@ -1795,6 +3310,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Operation not allowed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "404": {
                         "description": "User not found",
                         "schema": {
@@ -1816,44 +3340,63 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/search": {
+        "/users/{id}/avatar": {
             "get": {
-                "description": "Search users by query string",
+                "description": "Redirect to the user's avatar, or render an identicon when the user has none.\nIdenticons are derived from the user ID. Placeholder users (ID 0) are identified by the username parameter.",
                 "produces": [
-                    "application/json"
+                    "image/svg+xml",
+                    "image/png"
                 ],
                 "tags": [
                     "users"
                 ],
-                "summary": "Search users",
+                "summary": "Get user avatar",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Search query",
-                        "name": "query",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "User ID, or 0 for a placeholder user",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
+                        "type": "string",
+                        "description": "Username of a placeholder user",
+                        "name": "username",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "svg",
+                            "png"
+                        ],
+                        "type": "string",
+                        "default": "svg",
+                        "description": "Identicon format",
+                        "name": "format",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Results per page (max 100)",
-                        "name": "limit",
+                        "default": 128,
+                        "description": "Identicon PNG size in pixels (16-512)",
+                        "name": "size",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Search results",
+                        "description": "Identicon",
                         "schema": {
-                            "$ref": "#/definitions/user_handler.SearchUsersResponse"
+                            "type": "file"
                         }
                     },
+                    "302": {
+                        "description": "Redirect to the user's avatar"
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
                     "400": {
                         "description": "Bad request",
                         "schema": {
@@ -1863,8 +3406,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "User not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1884,32 +3427,54 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/username/{username}": {
+        "/users/{id}/posts.atom": {
             "get": {
-                "description": "Get user information by username",
+                "description": "Atom 1.0 feed of the user's latest posts, newest first, with media as enclosure links.\nSupports conditional requests with If-None-Match and If-Modified-Since.",
                 "produces": [
-                    "application/json"
+                    "application/atom+xml"
                 ],
                 "tags": [
                     "users"
                 ],
-                "summary": "Get user by username",
+                "summary": "Get a user's posts as Atom",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User username",
-                        "name": "username",
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of posts (1-50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified from a previous response",
+                        "name": "If-Modified-Since",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User information",
+                        "description": "Atom feed",
                         "schema": {
-                            "$ref": "#/definitions/user_handler.GetUserByUsernameResponse"
+                            "type": "string"
                         }
                     },
+                    "304": {
+                        "description": "Not modified"
+                    },
                     "400": {
                         "description": "Bad request",
                         "schema": {
@@ -1919,15 +3484,6 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
                     "404": {
                         "description": "User not found",
                         "schema": {
@@ -1949,32 +3505,54 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}": {
+        "/users/{id}/posts.rss": {
             "get": {
-                "description": "Get user information by user ID",
+                "description": "RSS 2.0 feed of the user's latest posts, newest first, with media as enclosures.\nSupports conditional requests with If-None-Match and If-Modified-Since.",
                 "produces": [
-                    "application/json"
+                    "application/rss+xml"
                 ],
                 "tags": [
                     "users"
                 ],
-                "summary": "Get user by ID",
+                "summary": "Get a user's posts as RSS",
                 "parameters": [
                     {
-                        "type": "string",
+                        "type": "integer",
                         "description": "User ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of posts (1-50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified from a previous response",
+                        "name": "If-Modified-Since",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User information",
+                        "description": "RSS feed",
                         "schema": {
-                            "$ref": "#/definitions/user_handler.GetUserResponse"
+                            "type": "string"
                         }
                     },
+                    "304": {
+                        "description": "Not modified"
+                    },
                     "400": {
                         "description": "Bad request",
                         "schema": {
@@ -1984,15 +3562,6 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
                     "404": {
                         "description": "User not found",
                         "schema": {
@@ -2012,21 +3581,21 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+            }
+        },
+        "/users/{id}/profile": {
+            "get": {
+                "description": "Get a user together with follower/followee counts, recent posts and, for authenticated callers, the caller's relationship to the user.\nSections other than the user are loaded concurrently with their own timeout; sections that fail are listed in missing_sections.\nAuthentication is optional; a bearer token that is sent must be valid.",
+                "produces": [
+                    "application/json"
                 ],
-                "description": "Delete a user by their ID",
                 "tags": [
                     "users"
                 ],
-                "summary": "Delete user by ID",
+                "summary": "Get user profile",
                 "parameters": [
                     {
-                        "type": "string",
+                        "type": "integer",
                         "description": "User ID",
                         "name": "id",
                         "in": "path",
@@ -2034,8 +3603,11 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "User deleted successfully"
+                    "200": {
+                        "description": "User profile",
+                        "schema": {
+                            "$ref": "#/definitions/profile_handler.ProfileResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad request",
@@ -2047,16 +3619,7 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "403": {
-                        "description": "Operation not allowed",
+                        "description": "Invalid bearer token",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2142,74 +3705,245 @@ const docTemplate = `{
                 "access_token": {
                     "type": "string"
                 },
-                "refresh_token": {
-                    "type": "string"
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth_handler.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "bio": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 3
+                }
+            }
+        },
+        "auth_handler.RegisterResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth_handler.UpdatePasswordRequest": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "old_password"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "old_password": {
+                    "type": "string",
+                    "minLength": 6
+                }
+            }
+        },
+        "auth_handler.UpdatePasswordResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "feed_handler.FeedItem": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "$ref": "#/definitions/userview.Author"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "media": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/feed_handler.FeedMedia"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/feed_handler.FeedTag"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "feed_handler.FeedMedia": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "feed_handler.FeedResponse": {
+            "type": "object",
+            "properties": {
+                "failed_authors": {
+                    "type": "integer"
+                },
+                "has_more": {
+                    "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "partial": {
+                    "type": "boolean"
+                },
+                "posts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/feed_handler.FeedItem"
+                    }
+                }
+            }
+        },
+        "feed_handler.FeedTag": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "media_handler.CreateUploadRequest": {
+            "type": "object",
+            "required": [
+                "size",
+                "type"
+            ],
+            "properties": {
+                "size": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "image",
+                        "video"
+                    ]
                 }
             }
         },
-        "auth_handler.RegisterRequest": {
+        "media_handler.MediaResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "password",
-                "username"
-            ],
             "properties": {
-                "avatar_url": {
+                "content_type": {
                     "type": "string"
                 },
-                "bio": {
+                "height": {
+                    "type": "integer"
+                },
+                "key": {
                     "type": "string"
                 },
-                "email": {
+                "size": {
+                    "type": "integer"
+                },
+                "type": {
                     "type": "string"
                 },
-                "full_name": {
+                "url": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 6
+                "variants": {
+                    "$ref": "#/definitions/models.MediaVariants"
                 },
-                "username": {
-                    "type": "string",
-                    "maxLength": 32,
-                    "minLength": 3
+                "width": {
+                    "type": "integer"
                 }
             }
         },
-        "auth_handler.RegisterResponse": {
+        "media_handler.UploadResponse": {
             "type": "object",
             "properties": {
-                "access_token": {
+                "expires_at": {
                     "type": "string"
                 },
-                "refresh_token": {
+                "offset": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "type": {
                     "type": "string"
-                }
-            }
-        },
-        "auth_handler.UpdatePasswordRequest": {
-            "type": "object",
-            "required": [
-                "new_password",
-                "old_password"
-            ],
-            "properties": {
-                "new_password": {
-                    "type": "string",
-                    "minLength": 6
                 },
-                "old_password": {
-                    "type": "string",
-                    "minLength": 6
+                "upload_id": {
+                    "type": "string"
                 }
             }
         },
-        "auth_handler.UpdatePasswordResponse": {
+        "models.MediaVariants": {
             "type": "object",
             "properties": {
-                "message": {
+                "full": {
+                    "type": "string"
+                },
+                "medium": {
+                    "type": "string"
+                },
+                "thumbnail": {
                     "type": "string"
                 }
             }
@@ -2241,14 +3975,50 @@ const docTemplate = `{
                 "avatar_url": {
                     "type": "string"
                 },
+                "bio": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "is_followed_by_me": {
+                    "type": "boolean"
+                },
                 "username": {
                     "type": "string"
                 }
             }
         },
+        "moderation.Action": {
+            "type": "integer",
+            "enum": [
+                0,
+                1,
+                2
+            ],
+            "x-enum-varnames": [
+                "Pass",
+                "Flag",
+                "Reject"
+            ]
+        },
+        "moderation.Reason": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/moderation.Action"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                }
+            }
+        },
         "notification_handler.GetUnreadCountResponse": {
             "type": "object",
             "properties": {
@@ -2260,6 +4030,9 @@ const docTemplate = `{
         "notification_handler.GetUserNotificationFeedSwaggerResponse": {
             "type": "object",
             "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
                 "limit": {
                     "type": "integer"
                 },
@@ -2269,6 +4042,9 @@ const docTemplate = `{
                         "$ref": "#/definitions/models.NotificationSwagger"
                     }
                 },
+                "offset": {
+                    "type": "integer"
+                },
                 "page": {
                     "type": "integer"
                 },
@@ -2327,6 +4103,32 @@ const docTemplate = `{
                 }
             }
         },
+        "post_handler.BatchPostsResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "description": "Failed lists requested IDs that could not be loaded because of a transient error; they may be retried.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "missing": {
+                    "description": "Missing lists requested IDs for which no post exists.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "posts": {
+                    "description": "Posts are in the order their IDs were requested.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/post_handler.ListPostItem"
+                    }
+                }
+            }
+        },
         "post_handler.CreatePostRequest": {
             "type": "object",
             "required": [
@@ -2359,25 +4161,16 @@ const docTemplate = `{
         "post_handler.CreatePostResponse": {
             "type": "object",
             "properties": {
-                "author_avatar_url": {
-                    "type": "string"
-                },
-                "author_bio": {
-                    "type": "string"
-                },
-                "author_email": {
-                    "type": "string"
-                },
-                "author_full_name": {
-                    "type": "string"
+                "author": {
+                    "$ref": "#/definitions/userview.Author"
                 },
                 "author_id": {
                     "type": "integer"
                 },
-                "author_username": {
+                "content": {
                     "type": "string"
                 },
-                "content": {
+                "content_html": {
                     "type": "string"
                 },
                 "created_at": {
@@ -2392,6 +4185,12 @@ const docTemplate = `{
                         "$ref": "#/definitions/post_handler.PostMediaResponse"
                     }
                 },
+                "mentions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/post_handler.MentionResponse"
+                    }
+                },
                 "tags": {
                     "type": "array",
                     "items": {
@@ -2420,6 +4219,9 @@ const docTemplate = `{
                 },
                 "url": {
                     "type": "string"
+                },
+                "variants": {
+                    "$ref": "#/definitions/models.MediaVariants"
                 }
             }
         },
@@ -2427,11 +4229,14 @@ const docTemplate = `{
             "type": "object",
             "properties": {
                 "author": {
-                    "$ref": "#/definitions/post_handler.GetPostUser"
+                    "$ref": "#/definitions/userview.Author"
                 },
                 "content": {
                     "type": "string"
                 },
+                "content_html": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
@@ -2469,49 +4274,18 @@ const docTemplate = `{
                 }
             }
         },
-        "post_handler.GetPostUser": {
-            "type": "object",
-            "properties": {
-                "avatar_url": {
-                    "type": "string"
-                },
-                "full_name": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "username": {
-                    "type": "string"
-                }
-            }
-        },
-        "post_handler.ListPostAuthor": {
-            "type": "object",
-            "properties": {
-                "avatar_url": {
-                    "type": "string"
-                },
-                "full_name": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "username": {
-                    "type": "string"
-                }
-            }
-        },
         "post_handler.ListPostItem": {
             "type": "object",
             "properties": {
                 "author": {
-                    "$ref": "#/definitions/post_handler.ListPostAuthor"
+                    "$ref": "#/definitions/userview.Author"
                 },
                 "content": {
                     "type": "string"
                 },
+                "content_html": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
@@ -2541,14 +4315,35 @@ const docTemplate = `{
         "post_handler.ListPostsResponse": {
             "type": "object",
             "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
                 "posts": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/post_handler.ListPostItem"
                     }
                 },
+                "prev_cursor": {
+                    "type": "string"
+                },
                 "total": {
                     "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
                 }
             }
         },
@@ -2577,6 +4372,31 @@ const docTemplate = `{
                 }
             }
         },
+        "post_handler.MentionResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "post_handler.ModerationRejectedResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/moderation.Reason"
+                    }
+                }
+            }
+        },
         "post_handler.PostMediaResponse": {
             "type": "object",
             "properties": {
@@ -2591,6 +4411,9 @@ const docTemplate = `{
                 },
                 "url": {
                     "type": "string"
+                },
+                "variants": {
+                    "$ref": "#/definitions/models.MediaVariants"
                 }
             }
         },
@@ -2600,45 +4423,115 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
-                "name": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "post_handler.UpdatePostRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "media_items": {
+                    "type": "array",
+                    "maxItems": 9,
+                    "items": {
+                        "$ref": "#/definitions/post_handler.MediaItemInput"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "post_handler.UpdatePostResponse": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "$ref": "#/definitions/userview.Author"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "media": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/post_handler.PostMediaResponse"
+                    }
+                },
+                "mentions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/post_handler.MentionResponse"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/post_handler.TagResponse"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "post_handler.UpdatePostAuthor": {
+        "profile_handler.ProfileMedia": {
             "type": "object",
             "properties": {
-                "avatar_url": {
-                    "type": "string"
-                },
-                "full_name": {
-                    "type": "string"
-                },
                 "id": {
                     "type": "integer"
                 },
-                "username": {
+                "position": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "post_handler.UpdatePostRequest": {
+        "profile_handler.ProfilePost": {
             "type": "object",
             "properties": {
                 "content": {
                     "type": "string"
                 },
-                "media_items": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "media": {
                     "type": "array",
-                    "maxItems": 9,
                     "items": {
-                        "$ref": "#/definitions/post_handler.MediaItemInput"
+                        "$ref": "#/definitions/profile_handler.ProfileMedia"
                     }
                 },
                 "tags": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/profile_handler.ProfileTag"
                     }
                 },
                 "title": {
@@ -2646,37 +4539,48 @@ const docTemplate = `{
                 }
             }
         },
-        "post_handler.UpdatePostResponse": {
+        "profile_handler.ProfileResponse": {
             "type": "object",
             "properties": {
-                "author": {
-                    "$ref": "#/definitions/post_handler.UpdatePostAuthor"
-                },
-                "content": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
+                "followees_count": {
+                    "type": "integer"
                 },
-                "id": {
+                "followers_count": {
                     "type": "integer"
                 },
-                "media": {
+                "missing_sections": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/post_handler.PostMediaResponse"
+                        "type": "string"
                     }
                 },
-                "tags": {
+                "partial": {
+                    "type": "boolean"
+                },
+                "posts_count": {
+                    "type": "integer"
+                },
+                "recent_posts": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/post_handler.TagResponse"
+                        "$ref": "#/definitions/profile_handler.ProfilePost"
                     }
                 },
-                "title": {
-                    "type": "string"
+                "relationship": {
+                    "$ref": "#/definitions/relationship.Status"
                 },
-                "updated_at": {
+                "user": {
+                    "$ref": "#/definitions/userview.User"
+                }
+            }
+        },
+        "profile_handler.ProfileTag": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
                     "type": "string"
                 }
             }
@@ -2709,14 +4613,23 @@ const docTemplate = `{
                         "$ref": "#/definitions/models.RelationUser"
                     }
                 },
+                "has_more": {
+                    "type": "boolean"
+                },
                 "limit": {
                     "type": "integer"
                 },
+                "offset": {
+                    "type": "integer"
+                },
                 "page": {
                     "type": "integer"
                 },
                 "total": {
                     "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
                 }
             }
         },
@@ -2729,195 +4642,222 @@ const docTemplate = `{
                         "$ref": "#/definitions/models.RelationUser"
                     }
                 },
+                "has_more": {
+                    "type": "boolean"
+                },
                 "limit": {
                     "type": "integer"
                 },
+                "offset": {
+                    "type": "integer"
+                },
                 "page": {
                     "type": "integer"
                 },
                 "total": {
                     "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
                 }
             }
         },
-        "relation_handler.UnfollowRequest": {
+        "relation_handler.RelationshipStatusResponse": {
             "type": "object",
-            "required": [
-                "followee_id"
-            ],
             "properties": {
-                "followee_id": {
+                "followed_by": {
+                    "type": "boolean"
+                },
+                "following": {
+                    "type": "boolean"
+                },
+                "mutual": {
+                    "type": "boolean"
+                },
+                "unknown": {
+                    "description": "Unknown is set when the relationship could not be confirmed, so a false following or followed_by may be wrong.",
+                    "type": "boolean"
+                },
+                "user_id": {
                     "type": "integer"
                 }
             }
         },
-        "relation_handler.UnfollowResponse": {
+        "relation_handler.RelationshipStatusesResponse": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string"
+                "statuses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/relation_handler.RelationshipStatusResponse"
+                    }
                 }
             }
         },
-        "user_handler.CreateUserRequest": {
+        "relation_handler.SuggestedUser": {
             "type": "object",
-            "required": [
-                "email",
-                "password",
-                "username"
-            ],
             "properties": {
                 "avatar_url": {
                     "type": "string"
                 },
-                "bio": {
-                    "type": "string",
-                    "maxLength": 500
-                },
-                "email": {
+                "full_name": {
                     "type": "string"
                 },
-                "full_name": {
-                    "type": "string",
-                    "maxLength": 100
+                "id": {
+                    "type": "integer"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 6
+                "mutual_count": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
                 },
                 "username": {
-                    "type": "string",
-                    "maxLength": 32,
-                    "minLength": 3
+                    "type": "string"
                 }
             }
         },
-        "user_handler.CreateUserResponse": {
+        "relation_handler.SuggestionsResponse": {
             "type": "object",
             "properties": {
-                "avatar_url": {
-                    "type": "string"
-                },
-                "bio": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "full_name": {
-                    "type": "string"
-                },
-                "id": {
+                "suggestions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/relation_handler.SuggestedUser"
+                    }
+                }
+            }
+        },
+        "relation_handler.UnfollowRequest": {
+            "type": "object",
+            "required": [
+                "followee_id"
+            ],
+            "properties": {
+                "followee_id": {
                     "type": "integer"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "username": {
-                    "type": "string"
                 }
             }
         },
-        "user_handler.GetUserByEmailResponse": {
+        "relation_handler.UnfollowResponse": {
             "type": "object",
             "properties": {
-                "avatar_url": {
-                    "type": "string"
-                },
-                "bio": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "full_name": {
+                "message": {
                     "type": "string"
+                }
+            }
+        },
+        "relationship.Status": {
+            "type": "object",
+            "properties": {
+                "followed_by": {
+                    "type": "boolean"
                 },
-                "id": {
-                    "type": "integer"
+                "following": {
+                    "type": "boolean"
                 },
-                "updated_at": {
-                    "type": "string"
+                "mutual": {
+                    "type": "boolean"
                 },
-                "username": {
-                    "type": "string"
+                "unknown": {
+                    "description": "Unknown is set when the relationship could not be confirmed within the scan limits,\nso a false Following or FollowedBy may be wrong.",
+                    "type": "boolean"
                 }
             }
         },
-        "user_handler.GetUserByUsernameResponse": {
+        "share_handler.OEmbedResponse": {
             "type": "object",
             "properties": {
-                "avatar_url": {
+                "author_name": {
                     "type": "string"
                 },
-                "bio": {
-                    "type": "string"
+                "cache_age": {
+                    "type": "integer"
                 },
-                "created_at": {
+                "height": {
+                    "type": "integer"
+                },
+                "html": {
                     "type": "string"
                 },
-                "email": {
+                "provider_name": {
                     "type": "string"
                 },
-                "full_name": {
+                "provider_url": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "title": {
+                    "type": "string"
                 },
-                "updated_at": {
+                "type": {
                     "type": "string"
                 },
-                "username": {
+                "version": {
                     "type": "string"
+                },
+                "width": {
+                    "type": "integer"
                 }
             }
         },
-        "user_handler.GetUserResponse": {
+        "user_handler.CreateUserRequest": {
             "type": "object",
+            "required": [
+                "email",
+                "password",
+                "username"
+            ],
             "properties": {
                 "avatar_url": {
                     "type": "string"
                 },
                 "bio": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 500
                 },
                 "email": {
                     "type": "string"
                 },
                 "full_name": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
+                    "type": "string",
+                    "maxLength": 100
                 },
-                "updated_at": {
-                    "type": "string"
+                "password": {
+                    "type": "string",
+                    "minLength": 6
                 },
                 "username": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 3
                 }
             }
         },
         "user_handler.SearchUsersResponse": {
             "type": "object",
             "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
                 "total": {
                     "type": "integer"
                 },
+                "total_pages": {
+                    "type": "integer"
+                },
                 "users": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/user_handler.UserResponse"
+                        "$ref": "#/definitions/userview.User"
                     }
                 }
             }
@@ -2961,41 +4901,32 @@ const docTemplate = `{
                 }
             }
         },
-        "user_handler.UpdateUserResponse": {
+        "userview.Author": {
             "type": "object",
             "properties": {
                 "avatar_url": {
                     "type": "string"
                 },
-                "bio": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                },
                 "full_name": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "updated_at": {
-                    "type": "string"
-                },
                 "username": {
                     "type": "string"
                 }
             }
         },
-        "user_handler.UserResponse": {
+        "userview.User": {
             "type": "object",
             "properties": {
                 "avatar_url": {
                     "type": "string"
                 },
+                "avatar_variants": {
+                    "$ref": "#/definitions/models.MediaVariants"
+                },
                 "bio": {
                     "type": "string"
                 },
@@ -23,6 +23,7 @@ package api
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 	"pinstack-api-gateway/config"
@@ -73,7 +74,9 @@ func NewAPIServer(address string,
 
 func (s *APIServer) Run(cfg *config.Config) error {
 	s.router = NewRouter(s.log, s.userClient, s.authClient, s.postClient, s.relationClient, s.notificationClient, s.metricsProvider)
-	s.router.Setup(cfg)
+	if err := s.router.Setup(cfg); err != nil {
+		return err
+	}
 
 	s.server = &http.Server{
 		Addr:         s.address,
@@ -89,6 +92,15 @@ func (s *APIServer) Run(cfg *config.Config) error {
 	return s.server.ListenAndServe()
 }
 
+// Shutdown stops the HTTP server and then releases the router's background workers,
+// even when the server did not shut down cleanly. Either may be missing when Run failed early.
 func (s *APIServer) Shutdown(ctx context.Context) error {
-	return s.server.Shutdown(ctx)
+	var errs []error
+	if s.server != nil {
+		errs = append(errs, s.server.Shutdown(ctx))
+	}
+	if s.router != nil {
+		errs = append(errs, s.router.Close(ctx))
+	}
+	return errors.Join(errs...)
 }
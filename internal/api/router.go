@@ -1,21 +1,37 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"pinstack-api-gateway/config"
+	"pinstack-api-gateway/internal/avatar"
 	auth_client "pinstack-api-gateway/internal/clients/auth"
 	notification_client "pinstack-api-gateway/internal/clients/notification"
 	post_client "pinstack-api-gateway/internal/clients/post"
 	relation_client "pinstack-api-gateway/internal/clients/relation"
 	user_client "pinstack-api-gateway/internal/clients/user"
 	auth_handler "pinstack-api-gateway/internal/handlers/auth"
+	feed_handler "pinstack-api-gateway/internal/handlers/feed"
+	media_handler "pinstack-api-gateway/internal/handlers/media"
 	notification_handler "pinstack-api-gateway/internal/handlers/notification"
 	post_handler "pinstack-api-gateway/internal/handlers/post"
+	profile_handler "pinstack-api-gateway/internal/handlers/profile"
 	relation_handler "pinstack-api-gateway/internal/handlers/relation"
+	share_handler "pinstack-api-gateway/internal/handlers/share"
+	syndication_handler "pinstack-api-gateway/internal/handlers/syndication"
 	user_handler "pinstack-api-gateway/internal/handlers/user"
 	"pinstack-api-gateway/internal/logger"
+	"pinstack-api-gateway/internal/media"
 	"pinstack-api-gateway/internal/metrics"
 	"pinstack-api-gateway/internal/middlewares"
+	"pinstack-api-gateway/internal/moderation"
+	"pinstack-api-gateway/internal/notifier"
+	"pinstack-api-gateway/internal/pagination"
+	"pinstack-api-gateway/internal/relationship"
+	"pinstack-api-gateway/internal/userview"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -32,6 +48,8 @@ type Router struct {
 	relationClient     relation_client.RelationClient
 	notificationClient notification_client.NotificationClient
 	metricsProvider    metrics.MetricsProvider
+	dispatcher         *notifier.Dispatcher
+	moderator          *moderation.Moderator
 }
 
 func NewRouter(log *logger.Logger, userClient user_client.UserClient, authClient auth_client.AuthClient, postClient post_client.PostClient, relationClient relation_client.RelationClient, notificationClient notification_client.NotificationClient, metricsProvider metrics.MetricsProvider) *Router {
@@ -47,7 +65,11 @@ func NewRouter(log *logger.Logger, userClient user_client.UserClient, authClient
 	}
 }
 
-func (r *Router) Setup(cfg *config.Config) {
+func (r *Router) Setup(cfg *config.Config) error {
+	if cfg.Media.Signing.Enabled && cfg.Media.Signing.Secret == "" {
+		return errors.New("media.signing.secret must be set when media.signing.enabled is true")
+	}
+
 	r.router.Use(middleware.RequestID)
 	r.router.Use(middleware.RealIP)
 	r.router.Use(middleware.Recoverer)
@@ -55,28 +77,135 @@ func (r *Router) Setup(cfg *config.Config) {
 	r.router.Use(middlewares.MetricsMiddleware(r.metricsProvider))
 	r.router.Use(middlewares.AuthMetricsMiddleware(r.metricsProvider))
 	r.router.Use(middleware.Timeout(time.Duration(cfg.HTTPServer.Timeout) * time.Second))
+	if cfg.Compression.Enabled {
+		r.router.Use(middlewares.CompressionMiddleware(middlewares.CompressionOptions{
+			MinSize:      cfg.Compression.MinSize,
+			GzipLevel:    cfg.Compression.GzipLevel,
+			ContentTypes: cfg.Compression.ContentTypes,
+		}))
+	}
 
 	jwtMiddleware := middlewares.JWTValidationMiddleware(cfg.JWT.Secret, r.log)
+	optionalJWTMiddleware := middlewares.OptionalJWTMiddleware(cfg.JWT.Secret, r.log)
+
+	mediaStore, err := newMediaStore(cfg.Media)
+	if err != nil {
+		return fmt.Errorf("setup media store: %w", err)
+	}
+	var mediaSigner *media.URLSigner
+	if cfg.Media.Signing.Enabled {
+		mediaSigner = media.NewURLSigner(cfg.Media.Signing.Secret, time.Duration(cfg.Media.Signing.TTLSeconds)*time.Second)
+	}
+	mediaURLs := media.NewURLResolver(mediaStore, mediaSigner, cfg.Media.Signing.BaseURL)
+	mediaHandler, err := r.newMediaHandler(mediaStore, mediaURLs, cfg.Media)
+	if err != nil {
+		return fmt.Errorf("setup media uploads: %w", err)
+	}
+
+	var postModerator post_handler.ContentModerator
+	if cfg.Moderation.Enabled {
+		r.moderator, err = newModerator(cfg.Moderation, r.log)
+		if err != nil {
+			return fmt.Errorf("setup moderation: %w", err)
+		}
+		postModerator = r.moderator
+	}
 
 	r.router.Get("/swagger/*", httpSwagger.WrapHandler)
+	r.router.Get("/media/*", mediaHandler.Serve)
+	r.router.Head("/media/*", mediaHandler.Serve)
+
+	avatars := avatar.NewLinks(cfg.Avatars.BaseURL)
+	views := userview.NewPolicy(cfg.Users.AdminIDs)
+	shareHandler := share_handler.NewShareHandler(r.postClient, r.userClient, mediaURLs, avatars, share_handler.Options{
+		BaseURL:  cfg.Share.BaseURL,
+		SiteName: cfg.Share.SiteName,
+		CacheTTL: time.Duration(cfg.Share.CacheTTLSeconds) * time.Second,
+		MaxAge:   time.Duration(cfg.Share.MaxAgeSeconds) * time.Second,
+	}, r.log)
+	r.router.Get("/p/{id}", shareHandler.Page)
+	r.router.Get("/oembed", shareHandler.OEmbed)
+
+	// Syndication feeds have their own XML representations, so they are routed ahead of
+	// /api/v1 and its content negotiation, which would turn feed readers away.
+	syndicationHandler := syndication_handler.NewSyndicationHandler(r.postClient, r.userClient, mediaURLs, syndication_handler.Options{
+		BaseURL:  cfg.Share.BaseURL,
+		SiteName: cfg.Share.SiteName,
+	}, r.log)
+	r.router.Get("/api/v1/users/{id}/posts.rss", syndicationHandler.GetRSS)
+	r.router.Get("/api/v1/users/{id}/posts.atom", syndicationHandler.GetAtom)
 
 	r.router.Route("/api/v1", func(v1 chi.Router) {
-		v1.Mount("/users", r.setupUserRoutes(jwtMiddleware))
+		v1.Use(middlewares.ContentNegotiationMiddleware(r.log))
+
+		cursors := pagination.NewCursorCodec(cfg.Pagination.CursorSecret)
+		var (
+			followHook      relation_handler.FollowHook
+			mentionNotifier post_handler.MentionNotifier
+		)
+		if cfg.Notifications.Follow.Enabled || cfg.Notifications.Mentions.Enabled {
+			r.dispatcher = notifier.NewDispatcher(r.notificationClient, notifier.Options{
+				Workers:     cfg.Notifications.Workers,
+				QueueSize:   cfg.Notifications.QueueSize,
+				MaxAttempts: cfg.Notifications.MaxAttempts,
+				RetryDelay:  time.Duration(cfg.Notifications.RetryDelayMs) * time.Millisecond,
+				DedupWindow: time.Duration(cfg.Notifications.DedupWindowSeconds) * time.Second,
+			}, r.log)
+		}
+		if cfg.Notifications.Follow.Enabled {
+			followHook = notifier.NewFollowNotifier(r.dispatcher, time.Duration(cfg.Notifications.Follow.DedupWindowSeconds)*time.Second, r.log)
+		}
+		if cfg.Notifications.Mentions.Enabled {
+			mentionNotifier = notifier.NewMentionNotifier(r.dispatcher, r.log)
+		}
+		relationships := relationship.NewResolver(r.relationClient, cfg.Relation.MaxScan, time.Duration(cfg.Relation.CacheTTLSeconds)*time.Second)
+
+		v1.Mount("/users", r.setupUserRoutes(jwtMiddleware, optionalJWTMiddleware, relationships, mediaURLs, views, cfg.Profile, cfg.Users))
 		v1.Mount("/auth", r.setupAuthRoutes(jwtMiddleware))
-		v1.Mount("/posts", r.setupPostRoutes(jwtMiddleware))
-		v1.Mount("/relation", r.setupRelationRoutes(jwtMiddleware))
+		v1.Mount("/posts", r.setupPostRoutes(jwtMiddleware, cursors, mentionNotifier, mediaURLs, avatars, postModerator))
+		v1.Mount("/feed", r.setupFeedRoutes(jwtMiddleware, cursors, avatars, mediaURLs, cfg.Feed))
+		v1.Mount("/relation", r.setupRelationRoutes(jwtMiddleware, optionalJWTMiddleware, relationships, avatars, mediaURLs, cfg.Relation.Suggestions, followHook))
 		v1.Mount("/notification", r.setupNotificationRoutes(jwtMiddleware))
+		v1.Mount("/media", r.setupMediaRoutes(jwtMiddleware, mediaHandler))
 	})
+
+	return nil
 }
 
-func (r *Router) setupUserRoutes(jwtMiddleware func(next http.Handler) http.Handler) http.Handler {
-	userHandler := user_handler.NewUserHandler(r.userClient, r.log)
+// Close flushes background work started by the router's handlers.
+func (r *Router) Close(ctx context.Context) error {
+	var errs []error
+	if r.dispatcher != nil {
+		errs = append(errs, r.dispatcher.Close(ctx))
+	}
+	if r.moderator != nil {
+		errs = append(errs, r.moderator.Close(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Router) setupUserRoutes(jwtMiddleware, optionalJWTMiddleware func(next http.Handler) http.Handler, relationships *relationship.Resolver, mediaURLs *media.URLResolver, views *userview.Policy, cfg config.Profile, usersCfg config.Users) http.Handler {
+	userHandler := user_handler.NewUserHandler(r.userClient, mediaURLs, views, r.log)
+	profileHandler := profile_handler.NewProfileHandler(r.userClient, r.relationClient, r.postClient, relationships, views, mediaURLs, profile_handler.Options{
+		SectionTimeout: time.Duration(cfg.SectionTimeoutMs) * time.Millisecond,
+		RecentPosts:    cfg.RecentPosts,
+	}, r.log)
 	router := chi.NewRouter()
 
-	router.Get("/{id}", userHandler.GetUser)
-	router.Get("/username/{username}", userHandler.GetUserByUsername)
-	router.Get("/email/{email}", userHandler.GetUserByEmail)
-	router.Get("/search", userHandler.SearchUsers)
+	emailLookupLimit := middlewares.RateLimitMiddleware(
+		middlewares.NewRateLimiter(usersCfg.EmailLookupLimit.Requests, time.Duration(usersCfg.EmailLookupLimit.WindowSeconds)*time.Second),
+		r.metricsProvider, r.log,
+	)
+
+	router.Get("/{id}/avatar", userHandler.GetAvatar)
+	router.Group(func(r chi.Router) {
+		// Signed-in callers may see more of their own account, so reads authenticate when they can.
+		r.Use(optionalJWTMiddleware)
+		r.Get("/{id}", userHandler.GetUser)
+		r.Get("/username/{username}", userHandler.GetUserByUsername)
+		r.Get("/search", userHandler.SearchUsers)
+		r.Get("/{id}/profile", profileHandler.GetProfile)
+	})
 
 	router.Group(func(r chi.Router) {
 		r.Use(jwtMiddleware)
@@ -84,6 +213,7 @@ func (r *Router) setupUserRoutes(jwtMiddleware func(next http.Handler) http.Hand
 		r.Put("/", userHandler.UpdateUser)
 		r.Delete("/{id}", userHandler.DeleteUser)
 		r.Put("/avatar", userHandler.UpdateAvatar)
+		r.With(emailLookupLimit).Get("/email/{email}", userHandler.GetUserByEmail)
 	})
 
 	return router
@@ -106,10 +236,11 @@ func (r *Router) setupAuthRoutes(jwtMiddleware func(next http.Handler) http.Hand
 	return router
 }
 
-func (r *Router) setupPostRoutes(jwtMiddleware func(next http.Handler) http.Handler) http.Handler {
-	postHandler := post_handler.NewPostHandler(r.postClient, r.userClient, r.log)
+func (r *Router) setupPostRoutes(jwtMiddleware func(next http.Handler) http.Handler, cursors *pagination.CursorCodec, mentionNotifier post_handler.MentionNotifier, mediaURLs *media.URLResolver, avatars *avatar.Links, moderator post_handler.ContentModerator) http.Handler {
+	postHandler := post_handler.NewPostHandler(r.postClient, r.userClient, cursors, mentionNotifier, mediaURLs, avatars, moderator, r.log)
 	router := chi.NewRouter()
 
+	router.Get("/", postHandler.Batch)
 	router.Get("/list", postHandler.List)
 	router.Get("/{id}", postHandler.Get)
 	router.Group(func(r chi.Router) {
@@ -122,15 +253,38 @@ func (r *Router) setupPostRoutes(jwtMiddleware func(next http.Handler) http.Hand
 	return router
 }
 
-func (r *Router) setupRelationRoutes(jwtMiddleware func(next http.Handler) http.Handler) http.Handler {
-	relationHandler := relation_handler.NewRelationHandler(r.relationClient, r.log)
+func (r *Router) setupFeedRoutes(jwtMiddleware func(next http.Handler) http.Handler, cursors *pagination.CursorCodec, avatars *avatar.Links, mediaURLs *media.URLResolver, cfg config.Feed) http.Handler {
+	feedHandler := feed_handler.NewFeedHandler(r.relationClient, r.postClient, r.userClient, cursors, avatars, mediaURLs, feed_handler.Options{
+		MaxFollowees: cfg.MaxFollowees,
+		Concurrency:  cfg.Concurrency,
+	}, r.log)
+	router := chi.NewRouter()
+
+	router.Group(func(r chi.Router) {
+		r.Use(jwtMiddleware)
+		r.Get("/", feedHandler.GetFeed)
+	})
+
+	return router
+}
+
+func (r *Router) setupRelationRoutes(jwtMiddleware, optionalJWTMiddleware func(next http.Handler) http.Handler, relationships *relationship.Resolver, avatars *avatar.Links, mediaURLs *media.URLResolver, cfg config.Suggestions, followHook relation_handler.FollowHook) http.Handler {
+	suggester := relationship.NewSuggester(r.relationClient, relationships, relationship.SuggesterOptions{
+		SampleSize:  cfg.SampleSize,
+		Concurrency: cfg.Concurrency,
+		CacheTTL:    time.Duration(cfg.CacheTTLSeconds) * time.Second,
+	})
+	relationHandler := relation_handler.NewRelationHandler(r.relationClient, r.userClient, relationships, suggester, followHook, avatars, mediaURLs, r.log)
 	router := chi.NewRouter()
-	router.Get("/{user_id}/followees", relationHandler.GetFollowees)
-	router.Get("/{user_id}/followers", relationHandler.GetFollowers)
+	router.With(optionalJWTMiddleware).Get("/{user_id}/followees", relationHandler.GetFollowees)
+	router.With(optionalJWTMiddleware).Get("/{user_id}/followers", relationHandler.GetFollowers)
 	router.Group(func(r chi.Router) {
 		r.Use(jwtMiddleware)
 		r.Post("/follow", relationHandler.Follow)
 		r.Post("/unfollow", relationHandler.Unfollow)
+		r.Get("/status", relationHandler.GetStatuses)
+		r.Get("/suggestions", relationHandler.GetSuggestions)
+		r.Get("/{user_id}/status", relationHandler.GetStatus)
 	})
 
 	return router
@@ -154,6 +308,83 @@ func (r *Router) setupNotificationRoutes(jwtMiddleware func(next http.Handler) h
 	return router
 }
 
+func newMediaStore(cfg config.Media) (media.MediaStore, error) {
+	switch cfg.Backend {
+	case "local":
+		return media.NewLocalStore(cfg.Local.Dir, cfg.Local.BaseURL)
+	case "s3":
+		return media.NewS3Store(media.S3Options{
+			Endpoint:  cfg.S3.Endpoint,
+			Region:    cfg.S3.Region,
+			Bucket:    cfg.S3.Bucket,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+			PublicURL: cfg.S3.PublicURL,
+			PathStyle: cfg.S3.PathStyle,
+		}, &http.Client{Timeout: 5 * time.Minute})
+	default:
+		return nil, fmt.Errorf("unknown media backend %q", cfg.Backend)
+	}
+}
+
+func (r *Router) newMediaHandler(store media.MediaStore, urls *media.URLResolver, cfg config.Media) (*media_handler.MediaHandler, error) {
+	uploads, err := media.NewUploads(cfg.UploadDir, time.Duration(cfg.UploadTTLSeconds)*time.Second, cfg.MaxOpenUploads)
+	if err != nil {
+		return nil, err
+	}
+
+	return media_handler.NewMediaHandler(store, uploads, urls, media_handler.Options{
+		Limits: media.Limits{
+			Image: cfg.MaxImageSize,
+			Video: cfg.MaxVideoSize,
+		},
+		MaxChunkSize: cfg.MaxChunkSize,
+		SpoolDir:     cfg.UploadDir,
+		Images: media.ImageOptions{
+			MaxPixels:     cfg.Images.MaxPixels,
+			ThumbnailSize: cfg.Images.ThumbnailSize,
+			MediumSize:    cfg.Images.MediumSize,
+			FullSize:      cfg.Images.FullSize,
+			JPEGQuality:   cfg.Images.JPEGQuality,
+		},
+	}, r.log), nil
+}
+
+func newModerator(cfg config.Moderation, log *logger.Logger) (*moderation.Moderator, error) {
+	var auditor moderation.Auditor = moderation.NewLogAuditor(log)
+	if cfg.AuditFile != "" {
+		fileAuditor, err := moderation.NewFileAuditor(cfg.AuditFile, log)
+		if err != nil {
+			return nil, err
+		}
+		auditor = fileAuditor
+	}
+
+	moderator, err := moderation.NewModerator(cfg.RulesFile, time.Duration(cfg.ReloadIntervalSeconds)*time.Second, auditor, log)
+	if err != nil {
+		if closer, ok := auditor.(io.Closer); ok {
+			closer.Close()
+		}
+		return nil, err
+	}
+	return moderator, nil
+}
+
+func (r *Router) setupMediaRoutes(jwtMiddleware func(next http.Handler) http.Handler, mediaHandler *media_handler.MediaHandler) http.Handler {
+	router := chi.NewRouter()
+
+	router.Group(func(r chi.Router) {
+		r.Use(jwtMiddleware)
+		r.Post("/", mediaHandler.Upload)
+		r.Post("/uploads", mediaHandler.CreateUpload)
+		r.Get("/uploads/{upload_id}", mediaHandler.GetUpload)
+		r.Put("/uploads/{upload_id}", mediaHandler.UploadChunk)
+		r.Delete("/uploads/{upload_id}", mediaHandler.CancelUpload)
+	})
+
+	return router
+}
+
 func (r *Router) GetRouter() *chi.Mux {
 	return r.router
 }
@@ -0,0 +1,129 @@
+package avatar
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	gridSize = 5
+	// gridMargin is the blank border around the grid, in cells.
+	gridMargin = 0.5
+)
+
+var background = color.RGBA{R: 0xF0, G: 0xF0, B: 0xF0, A: 0xFF}
+
+// Identicon is a symmetric 5x5 pattern and colour derived from a seed.
+// The same seed always yields the same identicon.
+type Identicon struct {
+	cells      [gridSize][gridSize]bool
+	foreground color.RGBA
+}
+
+// ForUserID seeds an identicon from a user ID, which unlike a username never changes.
+func ForUserID(id int64) *Identicon {
+	return New("id:" + strconv.FormatInt(id, 10))
+}
+
+// ForUsername seeds an identicon from a username, for users without a known ID.
+func ForUsername(username string) *Identicon {
+	return New("username:" + strings.ToLower(username))
+}
+
+func New(seed string) *Identicon {
+	sum := sha256.Sum256([]byte(seed))
+
+	ic := &Identicon{}
+	// Fill the left three columns and mirror them onto the right two.
+	for col := 0; col < (gridSize+1)/2; col++ {
+		for row := 0; row < gridSize; row++ {
+			on := sum[col*gridSize+row]&1 == 0
+			ic.cells[row][col] = on
+			ic.cells[row][gridSize-1-col] = on
+		}
+	}
+
+	hue := float64(int(sum[28])<<8|int(sum[29])) / 65536 * 360
+	saturation := 0.55 + float64(sum[30])/255*0.2
+	lightness := 0.45 + float64(sum[31])/255*0.15
+	ic.foreground = hslToRGB(hue, saturation, lightness)
+	return ic
+}
+
+// SVG renders the identicon as a scalable image.
+func (ic *Identicon) SVG() []byte {
+	var b bytes.Buffer
+	extent := gridSize + 2*gridMargin
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="%g %g %g %g" shape-rendering="crispEdges">`, -gridMargin, -gridMargin, extent, extent)
+	fmt.Fprintf(&b, `<rect x="%g" y="%g" width="%g" height="%g" fill="%s"/>`, -gridMargin, -gridMargin, extent, extent, hexColor(background))
+	fmt.Fprintf(&b, `<g fill="%s">`, hexColor(ic.foreground))
+	for row := 0; row < gridSize; row++ {
+		for col := 0; col < gridSize; col++ {
+			if ic.cells[row][col] {
+				fmt.Fprintf(&b, `<rect x="%d" y="%d" width="1" height="1"/>`, col, row)
+			}
+		}
+	}
+	b.WriteString(`</g></svg>`)
+	return b.Bytes()
+}
+
+// PNG renders the identicon as a size x size pixel image.
+func (ic *Identicon) PNG(size int) ([]byte, error) {
+	img := image.NewPaletted(image.Rect(0, 0, size, size), color.Palette{background, ic.foreground})
+	cell := float64(size) / (gridSize + 2*gridMargin)
+	for y := 0; y < size; y++ {
+		row := int(math.Floor(float64(y)/cell - gridMargin))
+		for x := 0; x < size; x++ {
+			col := int(math.Floor(float64(x)/cell - gridMargin))
+			if row >= 0 && row < gridSize && col >= 0 && col < gridSize && ic.cells[row][col] {
+				img.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func hslToRGB(h, s, l float64) color.RGBA {
+	c := (1 - math.Abs(2*l-1)) * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - c/2
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	return color.RGBA{
+		R: uint8(math.Round((r + m) * 255)),
+		G: uint8(math.Round((g + m) * 255)),
+		B: uint8(math.Round((b + m) * 255)),
+		A: 0xFF,
+	}
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,36 @@
+package avatar
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Links builds URLs of the avatar endpoint, which redirects to a user's avatar or renders their identicon.
+type Links struct {
+	base string
+}
+
+// NewLinks takes the public base URL of the users API, e.g. https://api.example.com/api/v1/users.
+func NewLinks(baseURL string) *Links {
+	return &Links{base: strings.TrimRight(baseURL, "/")}
+}
+
+// URL returns the avatar endpoint URL for a user. Placeholder users without an ID are
+// identified by username instead.
+func (l *Links) URL(userID int64, username string) string {
+	u := l.base + "/" + strconv.FormatInt(max(userID, 0), 10) + "/avatar"
+	if userID <= 0 && username != "" {
+		u += "?" + url.Values{"username": {username}}.Encode()
+	}
+	return u
+}
+
+// Or returns avatarURL when it is set, and the avatar endpoint URL for the user otherwise.
+func (l *Links) Or(avatarURL *string, userID int64, username string) *string {
+	if avatarURL != nil && *avatarURL != "" {
+		return avatarURL
+	}
+	u := l.URL(userID, username)
+	return &u
+}
@@ -2,10 +2,12 @@ package relation_client
 
 import (
 	"context"
+	"errors"
 	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
 	"log/slog"
 	"pinstack-api-gateway/internal/logger"
 	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/utils"
 
 	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/relation/v1"
 	"google.golang.org/grpc"
@@ -13,14 +15,24 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// resolveConcurrency bounds the user service calls made to resolve one page of relation users.
+const resolveConcurrency = 8
+
+// UserLookup finds users by username. It is used to resolve list entries the relation service returns without an ID.
+type UserLookup interface {
+	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
+}
+
 type relationClient struct {
 	client pb.RelationServiceClient
+	users  UserLookup
 	log    *logger.Logger
 }
 
-func NewRelationClient(conn *grpc.ClientConn, log *logger.Logger) RelationClient {
+func NewRelationClient(conn *grpc.ClientConn, users UserLookup, log *logger.Logger) RelationClient {
 	return &relationClient{
 		client: pb.NewRelationServiceClient(conn),
+		users:  users,
 		log:    log,
 	}
 }
@@ -119,7 +131,11 @@ func (c *relationClient) GetFollowers(ctx context.Context, followeeID int64, lim
 
 	followers := make([]*models.RelationUser, len(resp.Followers))
 	for i, user := range resp.Followers {
-		followers[i] = models.RelationUserFromProto(user)
+		followers[i] = models.RelationUserFromProto(user, followeeID)
+	}
+	followers, err = c.resolveIDs(ctx, followers)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	return followers, resp.Total, nil
@@ -155,8 +171,68 @@ func (c *relationClient) GetFollowees(ctx context.Context, followerID int64, lim
 
 	followees := make([]*models.RelationUser, len(resp.Followees))
 	for i, user := range resp.Followees {
-		followees[i] = models.RelationUserFromProto(user)
+		followees[i] = models.RelationUserFromProto(user, followerID)
+	}
+	followees, err = c.resolveIDs(ctx, followees)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	return followees, resp.Total, nil
 }
+
+// resolveIDs looks up the entries of one page that came back without an ID, so callers only ever see real IDs.
+// The user service has no batch endpoint, so the distinct usernames are resolved together with bounded
+// concurrency. Entries whose user no longer exists are dropped; any other failure fails the whole page.
+func (c *relationClient) resolveIDs(ctx context.Context, users []*models.RelationUser) ([]*models.RelationUser, error) {
+	usernames := make([]string, 0)
+	ids := make(map[string]int64)
+	for _, u := range users {
+		if _, ok := ids[u.Username]; u.ID == 0 && !ok {
+			ids[u.Username] = 0
+			usernames = append(usernames, u.Username)
+		}
+	}
+	if len(usernames) == 0 {
+		return users, nil
+	}
+
+	found := make([]int64, len(usernames))
+	errs := utils.FanOut(ctx, len(usernames), resolveConcurrency, func(ctx context.Context, i int) error {
+		user, err := c.users.GetUserByUsername(ctx, usernames[i])
+		if err != nil {
+			return err
+		}
+		found[i] = user.ID
+		return nil
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var firstErr error
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			ids[usernames[i]] = found[i]
+		case errors.Is(err, custom_errors.ErrUserNotFound):
+			c.log.Debug("Relation user no longer exists", slog.String("username", usernames[i]))
+		case firstErr == nil:
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		c.log.Error("Failed to resolve relation users", slog.String("error", firstErr.Error()))
+		return nil, custom_errors.ErrExternalServiceError
+	}
+
+	resolved := users[:0]
+	for _, u := range users {
+		if u.ID == 0 {
+			u.ID = ids[u.Username]
+		}
+		if u.ID != 0 {
+			resolved = append(resolved, u)
+		}
+	}
+	return resolved, nil
+}
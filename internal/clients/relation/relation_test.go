@@ -0,0 +1,146 @@
+package relation_client
+
+import (
+	"context"
+	"errors"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"io"
+	"log/slog"
+	"pinstack-api-gateway/internal/logger"
+	"pinstack-api-gateway/internal/models"
+	"sync"
+	"testing"
+
+	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/relation/v1"
+	"google.golang.org/grpc"
+)
+
+// fakeRelationService returns canned pages the way the relation service does: every entry of a
+// followee list carries the owner's ID in follower_id.
+type fakeRelationService struct {
+	pb.RelationServiceClient
+	followers []*pb.User
+	followees []*pb.User
+}
+
+func (f *fakeRelationService) GetFollowers(ctx context.Context, in *pb.GetFollowersRequest, opts ...grpc.CallOption) (*pb.GetFollowersResponse, error) {
+	return &pb.GetFollowersResponse{Followers: f.followers, Total: int64(len(f.followers))}, nil
+}
+
+func (f *fakeRelationService) GetFollowees(ctx context.Context, in *pb.GetFolloweesRequest, opts ...grpc.CallOption) (*pb.GetFolloweesResponse, error) {
+	return &pb.GetFolloweesResponse{Followees: f.followees, Total: int64(len(f.followees))}, nil
+}
+
+type fakeUsers struct {
+	mu    sync.Mutex
+	ids   map[string]int64
+	err   error
+	calls []string
+}
+
+func (f *fakeUsers) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, username)
+	if f.err != nil {
+		return nil, f.err
+	}
+	id, ok := f.ids[username]
+	if !ok {
+		return nil, custom_errors.ErrUserNotFound
+	}
+	return &models.User{ID: id, Username: username}, nil
+}
+
+func newTestClient(service *fakeRelationService, users *fakeUsers) *relationClient {
+	return &relationClient{
+		client: service,
+		users:  users,
+		log:    &logger.Logger{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))},
+	}
+}
+
+func relationUser(id int64, username string) *pb.User {
+	return &pb.User{FollowerId: id, Username: username}
+}
+
+func relationIDs(users []*models.RelationUser) map[string]int64 {
+	ids := make(map[string]int64, len(users))
+	for _, u := range users {
+		ids[u.Username] = u.ID
+	}
+	return ids
+}
+
+func TestGetFolloweesResolvesOwnerIDs(t *testing.T) {
+	const ownerID = 1
+	service := &fakeRelationService{followees: []*pb.User{
+		relationUser(ownerID, "bob"),
+		relationUser(ownerID, "carol"),
+		relationUser(ownerID, "bob"),
+	}}
+	users := &fakeUsers{ids: map[string]int64{"bob": 2, "carol": 3}}
+
+	followees, total, err := newTestClient(service, users).GetFollowees(context.Background(), ownerID, 10, 1)
+	if err != nil {
+		t.Fatalf("GetFollowees: %v", err)
+	}
+	if total != 3 || len(followees) != 3 {
+		t.Fatalf("got %d followees, total %d; want 3, 3", len(followees), total)
+	}
+	for _, u := range followees {
+		if want := users.ids[u.Username]; u.ID != want {
+			t.Errorf("%s: got ID %d, want %d", u.Username, u.ID, want)
+		}
+	}
+	if len(users.calls) != 2 {
+		t.Errorf("got %d username lookups, want one per distinct username: %v", len(users.calls), users.calls)
+	}
+}
+
+func TestGetFollowersKeepsReturnedIDs(t *testing.T) {
+	service := &fakeRelationService{followers: []*pb.User{
+		relationUser(2, "bob"),
+		relationUser(3, "carol"),
+	}}
+	users := &fakeUsers{}
+
+	followers, _, err := newTestClient(service, users).GetFollowers(context.Background(), 1, 10, 1)
+	if err != nil {
+		t.Fatalf("GetFollowers: %v", err)
+	}
+	if got := relationIDs(followers); got["bob"] != 2 || got["carol"] != 3 {
+		t.Errorf("got IDs %v, want bob=2 carol=3", got)
+	}
+	if len(users.calls) != 0 {
+		t.Errorf("looked up %v, want no lookups", users.calls)
+	}
+}
+
+func TestGetFolloweesDropsDeletedUsers(t *testing.T) {
+	const ownerID = 1
+	service := &fakeRelationService{followees: []*pb.User{
+		relationUser(ownerID, "bob"),
+		relationUser(ownerID, "gone"),
+	}}
+	users := &fakeUsers{ids: map[string]int64{"bob": 2}}
+
+	followees, _, err := newTestClient(service, users).GetFollowees(context.Background(), ownerID, 10, 1)
+	if err != nil {
+		t.Fatalf("GetFollowees: %v", err)
+	}
+	if len(followees) != 1 || followees[0].ID != 2 {
+		t.Errorf("got %v, want only bob with ID 2", relationIDs(followees))
+	}
+}
+
+func TestGetFolloweesFailsWhenLookupFails(t *testing.T) {
+	const ownerID = 1
+	service := &fakeRelationService{followees: []*pb.User{relationUser(ownerID, "bob")}}
+	users := &fakeUsers{err: errors.New("unavailable")}
+
+	_, _, err := newTestClient(service, users).GetFollowees(context.Background(), ownerID, 10, 1)
+	if !errors.Is(err, custom_errors.ErrExternalServiceError) {
+		t.Errorf("got error %v, want %v", err, custom_errors.ErrExternalServiceError)
+	}
+}
@@ -0,0 +1,65 @@
+package content
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+var excerptParser = goldmark.New().Parser()
+
+// Excerpt returns the plain text of Markdown source, with formatting, raw HTML and code blocks
+// dropped and whitespace collapsed, cut at a word boundary to at most maxRunes runes.
+func Excerpt(source string, maxRunes int) string {
+	src := []byte(source)
+	doc := excerptParser.Parse(text.NewReader(src))
+
+	var b strings.Builder
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		switch n := n.(type) {
+		case *ast.CodeBlock, *ast.FencedCodeBlock, *ast.HTMLBlock, *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			if entering {
+				b.Write(n.Segment.Value(src))
+				if n.SoftLineBreak() || n.HardLineBreak() {
+					b.WriteByte(' ')
+				}
+			}
+		case *ast.String:
+			if entering {
+				b.Write(n.Value)
+			}
+		case *ast.AutoLink:
+			if entering {
+				b.Write(n.Label(src))
+			}
+		default:
+			if !entering && n.Type() == ast.TypeBlock {
+				b.WriteByte(' ')
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	return truncateWords(strings.Join(strings.Fields(b.String()), " "), maxRunes)
+}
+
+// truncateWords cuts s to at most maxRunes runes, preferring a word boundary, and marks the cut with an ellipsis.
+func truncateWords(s string, maxRunes int) string {
+	runes := []rune(s)
+	if maxRunes <= 0 || len(runes) <= maxRunes {
+		return s
+	}
+	cut := string(runes[:maxRunes-1])
+	// Break at the last space unless that would throw away most of the text.
+	if i := strings.LastIndexFunc(cut, unicode.IsSpace); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRightFunc(cut, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + "…"
+}
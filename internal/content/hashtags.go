@@ -0,0 +1,166 @@
+package content
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// MaxTagLength is the longest tag accepted, in characters.
+	MaxTagLength = 50
+	// MaxTagsPerPost is how many tags a post may carry after explicit and extracted tags are merged.
+	MaxTagsPerPost = 10
+)
+
+// explicitTagPunctuation lists the characters explicit tags may contain besides tag characters.
+const explicitTagPunctuation = "+.-#"
+
+var (
+	ErrInvalidTag  = errors.New("invalid tag")
+	ErrTooManyTags = errors.New("too many tags")
+)
+
+// NormalizeTag applies the tag format policy: an optional leading # is dropped, the tag is NFC normalised
+// and lower-cased, and it must be 1-MaxTagLength letters, digits or underscores with at least one letter.
+func NormalizeTag(tag string) (string, error) {
+	return normalizeTag(tag, false)
+}
+
+// NormalizeExplicitTag applies the tag policy to a tag given explicitly rather than extracted from text.
+// Besides what NormalizeTag accepts it allows + . - and # after the first character, so tags such as
+// c++, c# or node.js keep their meaning.
+func NormalizeExplicitTag(tag string) (string, error) {
+	return normalizeTag(tag, true)
+}
+
+func normalizeTag(tag string, explicit bool) (string, error) {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	tag = strings.ToLower(norm.NFC.String(tag))
+	if tag == "" || utf8.RuneCountInString(tag) > MaxTagLength {
+		return "", ErrInvalidTag
+	}
+	hasLetter := false
+	for i, r := range tag {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case isTagRune(r):
+		case explicit && i > 0 && strings.ContainsRune(explicitTagPunctuation, r):
+		default:
+			return "", ErrInvalidTag
+		}
+	}
+	if !hasLetter {
+		return "", ErrInvalidTag
+	}
+	return tag, nil
+}
+
+// ExtractHashtags returns the normalised #hashtags in text in order of first appearance.
+// A hashtag starts at a # that does not follow a tag character or & (HTML entities), and runs over
+// letters, combining marks, digits and underscores. Candidates that fail NormalizeTag are skipped.
+func ExtractHashtags(text string) []string {
+	tags := make([]string, 0)
+	seen := make(map[string]struct{})
+	prev := rune(-1)
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if r != '#' || (prev != -1 && (isTagRune(prev) || prev == '&' || prev == '#')) {
+			prev = r
+			i += size
+			continue
+		}
+
+		end := i + size
+		for end < len(text) {
+			next, nextSize := utf8.DecodeRuneInString(text[end:])
+			if !isTagRune(next) {
+				break
+			}
+			end += nextSize
+		}
+
+		if tag, err := NormalizeTag(text[i+size : end]); err == nil {
+			if _, ok := seen[tag]; !ok {
+				seen[tag] = struct{}{}
+				tags = append(tags, tag)
+			}
+		}
+		prev, _ = utf8.DecodeLastRuneInString(text[:end])
+		i = end
+	}
+	return tags
+}
+
+// MergeTags appends the hashtags extracted from text to the explicit tags, without duplicates.
+// Blank explicit tags are skipped, the others must pass NormalizeExplicitTag and fit the per-post limit;
+// extracted tags beyond it are dropped.
+func MergeTags(explicit []string, text *string) ([]string, error) {
+	merged := make([]string, 0, len(explicit))
+	seen := make(map[string]struct{}, len(explicit))
+	for _, t := range explicit {
+		if strings.TrimSpace(t) == "" {
+			continue
+		}
+		tag, err := NormalizeExplicitTag(t)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		merged = append(merged, tag)
+	}
+	if len(merged) > MaxTagsPerPost {
+		return nil, ErrTooManyTags
+	}
+
+	if text == nil {
+		return merged, nil
+	}
+	for _, tag := range ExtractHashtags(*text) {
+		if len(merged) == MaxTagsPerPost {
+			break
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		merged = append(merged, tag)
+	}
+	return merged, nil
+}
+
+// WithoutHashtags returns tags except those extracted from text. It recovers the explicit tags of a
+// stored post so its hashtags can be recomputed when the content changes; an explicit tag that was
+// also a hashtag in text is dropped with them.
+func WithoutHashtags(tags []string, text *string) []string {
+	if text == nil {
+		return tags
+	}
+	hashtags := make(map[string]struct{})
+	for _, tag := range ExtractHashtags(*text) {
+		hashtags[tag] = struct{}{}
+	}
+	kept := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if _, ok := hashtags[tagKey(tag)]; !ok {
+			kept = append(kept, tag)
+		}
+	}
+	return kept
+}
+
+// tagKey is the form tags are compared in.
+func tagKey(tag string) string {
+	return strings.ToLower(norm.NFC.String(strings.TrimSpace(tag)))
+}
+
+func isTagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Mc, r) || r == '_'
+}
@@ -0,0 +1,130 @@
+package content
+
+import (
+	"bytes"
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// MarkdownRenderer turns post content written in CommonMark into sanitised HTML.
+// Raw HTML in the source is dropped, only basic formatting survives the allowlist, and every link
+// opens in a new tab with rel="nofollow noopener". Results are memoised per post version.
+type MarkdownRenderer struct {
+	md     goldmark.Markdown
+	policy *bluemonday.Policy
+
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[renderKey]*list.Element
+}
+
+type renderKey struct {
+	postID    int64
+	updatedAt int64
+}
+
+type renderEntry struct {
+	key  renderKey
+	html string
+}
+
+func NewMarkdownRenderer(cacheSize int) *MarkdownRenderer {
+	if cacheSize <= 0 {
+		cacheSize = 1000
+	}
+	return &MarkdownRenderer{
+		md: goldmark.New(
+			goldmark.WithParserOptions(
+				parser.WithASTTransformers(util.Prioritized(linkTargetTransformer{}, 100)),
+			),
+		),
+		policy:   sanitizePolicy(),
+		capacity: cacheSize,
+		order:    list.New(),
+		entries:  make(map[renderKey]*list.Element),
+	}
+}
+
+// Render returns the HTML for one version of a post's content.
+func (m *MarkdownRenderer) Render(postID int64, updatedAt time.Time, source string) (string, error) {
+	key := renderKey{postID: postID, updatedAt: updatedAt.UnixNano()}
+	if html, ok := m.cached(key); ok {
+		return html, nil
+	}
+
+	var buf bytes.Buffer
+	if err := m.md.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	html := m.policy.Sanitize(buf.String())
+
+	m.store(key, html)
+	return html, nil
+}
+
+func (m *MarkdownRenderer) cached(key renderKey) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	el, ok := m.entries[key]
+	if !ok {
+		return "", false
+	}
+	m.order.MoveToFront(el)
+	return el.Value.(*renderEntry).html, true
+}
+
+func (m *MarkdownRenderer) store(key renderKey, html string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, ok := m.entries[key]; ok {
+		m.order.MoveToFront(el)
+		return
+	}
+	m.entries[key] = m.order.PushFront(&renderEntry{key: key, html: html})
+	for m.order.Len() > m.capacity {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*renderEntry).key)
+	}
+}
+
+func sanitizePolicy() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+	p.AllowElements("p", "br", "hr", "em", "strong", "code", "pre", "blockquote", "ul", "ol", "li",
+		"h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("start").Matching(bluemonday.Integer).OnElements("ol")
+	p.AllowAttrs("href").OnElements("a")
+	p.AllowAttrs("target").Matching(bluemonday.Paragraph).OnElements("a")
+	p.AllowURLSchemes("http", "https", "mailto")
+	p.AllowRelativeURLs(true)
+	p.RequireParseableURLs(true)
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}
+
+// linkTargetTransformer marks every link target="_blank" so the sanitiser adds rel="noopener"
+// to relative links too, not just fully qualified ones.
+type linkTargetTransformer struct{}
+
+func (linkTargetTransformer) Transform(node *ast.Document, _ text.Reader, _ parser.Context) {
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n.Kind() {
+		case ast.KindLink, ast.KindAutoLink:
+			n.SetAttributeString("target", []byte("_blank"))
+		}
+		return ast.WalkContinue, nil
+	})
+}
@@ -0,0 +1,27 @@
+package content
+
+import (
+	"regexp"
+	"strings"
+)
+
+// mentionPattern matches @username where the username follows the registration rules (3-32 characters)
+// and the @ is not part of a word or an email address.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@([A-Za-z0-9_][A-Za-z0-9_.]{1,30}[A-Za-z0-9_])\b`)
+
+// ExtractMentions returns the usernames mentioned in text in order of first appearance.
+// Duplicates are compared case-insensitively and the first spelling is kept.
+func ExtractMentions(text string) []string {
+	matches := mentionPattern.FindAllStringSubmatch(text, -1)
+	seen := make(map[string]struct{}, len(matches))
+	usernames := make([]string, 0, len(matches))
+	for _, m := range matches {
+		key := strings.ToLower(m[1])
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		usernames = append(usernames, m[1])
+	}
+	return usernames
+}
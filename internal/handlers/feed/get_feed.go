@@ -0,0 +1,301 @@
+package feed_handler
+
+import (
+	"context"
+	"errors"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"log/slog"
+	"net/http"
+	"pinstack-api-gateway/internal/middlewares"
+	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/pagination"
+	"pinstack-api-gateway/internal/userview"
+	"pinstack-api-gateway/internal/utils"
+	"strconv"
+	"time"
+)
+
+// feedTieSlack is how many extra posts are requested per author so posts sharing the cursor timestamp can be skipped.
+const feedTieSlack = 10
+
+// followeesPageSize is the page size used when walking the caller's followees.
+const followeesPageSize = 100
+
+type FeedResponse struct {
+	Posts         []FeedItem `json:"posts"`
+	Limit         int        `json:"limit"`
+	HasMore       bool       `json:"has_more"`
+	NextCursor    string     `json:"next_cursor,omitempty"`
+	Partial       bool       `json:"partial,omitempty"`
+	FailedAuthors int        `json:"failed_authors,omitempty"`
+}
+
+type FeedItem struct {
+	ID        int64            `json:"id"`
+	Title     string           `json:"title"`
+	Content   *string          `json:"content,omitempty"`
+	CreatedAt string           `json:"created_at"`
+	UpdatedAt string           `json:"updated_at"`
+	Author    *userview.Author `json:"author,omitempty"`
+	Media     []FeedMedia      `json:"media,omitempty"`
+	Tags      []FeedTag        `json:"tags,omitempty"`
+}
+
+type FeedMedia struct {
+	ID       int64  `json:"id"`
+	URL      string `json:"url"`
+	Type     string `json:"type"`
+	Position int32  `json:"position"`
+}
+
+type FeedTag struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// GetFeed godoc
+// @Summary Get home timeline
+// @Description Get the newest posts of the users the caller follows, merged into one timeline.
+// @Description Pages are cursor based; pass next_cursor back to continue. If some authors could not be loaded the page is still returned with partial set.
+// @Tags feed
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param cursor query string false "Opaque cursor from next_cursor"
+// @Success 200 {object} FeedResponse "Home timeline page"
+// @Header 200 {string} Link "RFC 8288 pagination links"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /feed [get]
+func (h *FeedHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	claims, err := middlewares.GetClaimsFromContext(r.Context())
+	if err != nil {
+		h.log.Debug("No user claims in context", slog.String("error", err.Error()))
+		utils.SendError(w, http.StatusUnauthorized, custom_errors.ErrUnauthenticated.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	if query.Get("page") != "" || query.Get("offset") != "" {
+		h.log.Debug("feed only supports cursor pagination")
+		utils.SendError(w, http.StatusBadRequest, pagination.ErrInvalidPagination.Error())
+		return
+	}
+	params, err := pagination.Parse(query)
+	if err != nil {
+		h.log.Debug("Invalid pagination parameters", slog.String("error", err.Error()))
+		utils.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filters := map[string]string{"user_id": strconv.FormatInt(claims.UserID, 10)}
+	var cursor *pagination.Cursor
+	if cursorStr := query.Get("cursor"); cursorStr != "" {
+		c, err := h.cursors.Decode(cursorStr)
+		if err != nil || c.Direction != pagination.DirectionNext || !pagination.SameFilters(c.Filters, filters) {
+			h.log.Debug("Invalid feed cursor", slog.Int64("user_id", claims.UserID))
+			utils.SendError(w, http.StatusBadRequest, pagination.ErrInvalidCursor.Error())
+			return
+		}
+		cursor = &c
+	}
+
+	followees, err := h.listFollowees(r.Context(), claims.UserID)
+	if err != nil {
+		h.log.Error("Failed to get followees", slog.Int64("user_id", claims.UserID), slog.String("error", err.Error()))
+		switch {
+		case errors.Is(err, custom_errors.ErrUserNotFound):
+			utils.SendError(w, http.StatusNotFound, custom_errors.ErrUserNotFound.Error())
+		default:
+			utils.SendError(w, http.StatusInternalServerError, custom_errors.ErrExternalServiceError.Error())
+		}
+		return
+	}
+
+	authorIDs := make([]int64, 0, len(followees))
+	for id := range followees {
+		authorIDs = append(authorIDs, id)
+	}
+
+	lists := make([][]*models.PostDetailed, len(authorIDs))
+	errs := utils.FanOut(r.Context(), len(authorIDs), h.opts.Concurrency, func(ctx context.Context, i int) error {
+		posts, err := h.authorPosts(ctx, authorIDs[i], cursor, params.Limit)
+		lists[i] = posts
+		return err
+	})
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			failed++
+			h.log.Warn("Failed to load feed posts for author", slog.Int64("author_id", authorIDs[i]), slog.String("error", err.Error()))
+		}
+	}
+	if failed > 0 && failed == len(authorIDs) {
+		h.log.Error("Failed to load feed posts for every followee", slog.Int64("user_id", claims.UserID), slog.Int("followees", len(authorIDs)))
+		utils.SendError(w, http.StatusInternalServerError, custom_errors.ErrExternalServiceError.Error())
+		return
+	}
+
+	posts := mergeNewestFirst(lists, params.Limit+1)
+	hasMore := len(posts) > params.Limit
+	if hasMore {
+		posts = posts[:params.Limit]
+	}
+
+	resp := FeedResponse{
+		Posts:         make([]FeedItem, len(posts)),
+		Limit:         params.Limit,
+		HasMore:       hasMore,
+		Partial:       failed > 0,
+		FailedAuthors: failed,
+	}
+	if hasMore {
+		last := posts[len(posts)-1].Post
+		resp.NextCursor, err = h.cursors.Encode(pagination.Cursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+			Direction: pagination.DirectionNext,
+			Filters:   filters,
+		})
+		if err != nil {
+			h.log.Error("Failed to encode cursor", slog.String("error", err.Error()))
+			utils.SendError(w, http.StatusInternalServerError, custom_errors.ErrExternalServiceError.Error())
+			return
+		}
+	}
+
+	authors := h.loadAuthors(r.Context(), posts, followees)
+	for i, p := range posts {
+		resp.Posts[i] = h.feedItem(p, authors[p.Post.AuthorID])
+	}
+
+	if resp.NextCursor != "" {
+		links := map[string]map[string]string{"next": {"cursor": resp.NextCursor}}
+		w.Header().Set("Link", pagination.LinkHeader(r, links, "next"))
+	}
+	utils.Send(w, http.StatusOK, resp)
+}
+
+// listFollowees walks the caller's followees page by page, up to Options.MaxFollowees.
+func (h *FeedHandler) listFollowees(ctx context.Context, userID int64) (map[int64]*models.RelationUser, error) {
+	followees := make(map[int64]*models.RelationUser)
+	for page := int32(1); len(followees) < h.opts.MaxFollowees; page++ {
+		users, total, err := h.relationClient.GetFollowees(ctx, userID, followeesPageSize, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			if len(followees) == h.opts.MaxFollowees {
+				break
+			}
+			followees[u.ID] = u
+		}
+		if len(users) < followeesPageSize || int64(page)*followeesPageSize >= total {
+			break
+		}
+	}
+	return followees, nil
+}
+
+// authorPosts returns up to limit+1 posts of one author that come after cursor in feed order.
+func (h *FeedHandler) authorPosts(ctx context.Context, authorID int64, cursor *pagination.Cursor, limit int) ([]*models.PostDetailed, error) {
+	offset, fetchLimit := 0, limit+1
+	filters := models.PostFilters{AuthorID: &authorID, Offset: &offset, Limit: &fetchLimit}
+	if cursor != nil {
+		fetchLimit += feedTieSlack
+		before := cursor.CreatedAt.Add(time.Microsecond)
+		filters.CreatedBefore = &before
+	}
+
+	posts, _, err := h.postClient.ListPosts(ctx, &filters)
+	if err != nil || cursor == nil {
+		return posts, err
+	}
+
+	older := posts[:0]
+	for _, p := range posts {
+		if p.Post.CreatedAt.Before(cursor.CreatedAt) || (p.Post.CreatedAt.Equal(cursor.CreatedAt) && p.Post.ID < cursor.ID) {
+			older = append(older, p)
+		}
+	}
+	return older, nil
+}
+
+// loadAuthors resolves the authors of a page. Lookups that fail fall back to what the relation service returned.
+func (h *FeedHandler) loadAuthors(ctx context.Context, posts []*models.PostDetailed, followees map[int64]*models.RelationUser) map[int64]*userview.Author {
+	ids := make([]int64, 0, len(posts))
+	seen := make(map[int64]struct{}, len(posts))
+	for _, p := range posts {
+		if _, ok := seen[p.Post.AuthorID]; ok {
+			continue
+		}
+		seen[p.Post.AuthorID] = struct{}{}
+		ids = append(ids, p.Post.AuthorID)
+	}
+
+	users := make([]*models.User, len(ids))
+	errs := utils.FanOut(ctx, len(ids), h.opts.Concurrency, func(ctx context.Context, i int) error {
+		user, err := h.userClient.GetUser(ctx, ids[i])
+		users[i] = user
+		return err
+	})
+
+	authors := make(map[int64]*userview.Author, len(ids))
+	for i, id := range ids {
+		user := users[i]
+		switch {
+		case errs[i] == nil:
+		case errors.Is(errs[i], custom_errors.ErrUserNotFound):
+			h.log.Warn("author not found, using placeholder", slog.Int64("authorID", id))
+			user = utils.GenerateUnknownAuthor()
+		default:
+			h.log.Warn("Failed to get feed author, using relation data", slog.Int64("authorID", id), slog.String("error", errs[i].Error()))
+			if f, ok := followees[id]; ok {
+				authors[id] = &userview.Author{ID: id, Username: f.Username, AvatarURL: h.avatarURL(f.AvatarURL, id, f.Username)}
+				continue
+			}
+			user = utils.GenerateUnknownAuthor()
+		}
+		authors[id] = userview.NewAuthor(user, h.avatarURL(user.AvatarURL, user.ID, user.Username))
+	}
+	return authors
+}
+
+// avatarURL returns the avatar to show for an author: their own, or the identicon served by the avatar endpoint.
+func (h *FeedHandler) avatarURL(avatarURL *string, userID int64, username string) *string {
+	return h.mediaURLs.PublicPtr(h.avatars.Or(avatarURL, userID, username))
+}
+
+func (h *FeedHandler) feedItem(p *models.PostDetailed, author *userview.Author) FeedItem {
+	item := FeedItem{
+		ID:        p.Post.ID,
+		Title:     p.Post.Title,
+		Content:   p.Post.Content,
+		CreatedAt: p.Post.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt: p.Post.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Author:    author,
+	}
+	if len(p.Media) > 0 {
+		item.Media = make([]FeedMedia, len(p.Media))
+		for j, m := range p.Media {
+			item.Media[j] = FeedMedia{
+				ID:       m.ID,
+				URL:      h.mediaURLs.Public(m.URL),
+				Type:     string(m.Type),
+				Position: m.Position,
+			}
+		}
+	}
+	if len(p.Tags) > 0 {
+		item.Tags = make([]FeedTag, len(p.Tags))
+		for k, t := range p.Tags {
+			item.Tags[k] = FeedTag{
+				ID:   t.ID,
+				Name: t.Name,
+			}
+		}
+	}
+	return item
+}
@@ -0,0 +1,47 @@
+package feed_handler
+
+import (
+	"pinstack-api-gateway/internal/avatar"
+	post_client "pinstack-api-gateway/internal/clients/post"
+	relation_client "pinstack-api-gateway/internal/clients/relation"
+	user_client "pinstack-api-gateway/internal/clients/user"
+	"pinstack-api-gateway/internal/logger"
+	"pinstack-api-gateway/internal/media"
+	"pinstack-api-gateway/internal/pagination"
+)
+
+// Options bounds how much work a single feed request may fan out.
+type Options struct {
+	MaxFollowees int
+	Concurrency  int
+}
+
+type FeedHandler struct {
+	relationClient relation_client.RelationClient
+	postClient     post_client.PostClient
+	userClient     user_client.UserClient
+	cursors        *pagination.CursorCodec
+	avatars        *avatar.Links
+	mediaURLs      *media.URLResolver
+	opts           Options
+	log            *logger.Logger
+}
+
+func NewFeedHandler(relationClient relation_client.RelationClient, postClient post_client.PostClient, userClient user_client.UserClient, cursors *pagination.CursorCodec, avatars *avatar.Links, mediaURLs *media.URLResolver, opts Options, log *logger.Logger) *FeedHandler {
+	if opts.MaxFollowees <= 0 {
+		opts.MaxFollowees = 500
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 8
+	}
+	return &FeedHandler{
+		relationClient: relationClient,
+		postClient:     postClient,
+		userClient:     userClient,
+		cursors:        cursors,
+		avatars:        avatars,
+		mediaURLs:      mediaURLs,
+		opts:           opts,
+		log:            log,
+	}
+}
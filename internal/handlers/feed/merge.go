@@ -0,0 +1,58 @@
+package feed_handler
+
+import (
+	"container/heap"
+	"pinstack-api-gateway/internal/models"
+	"sort"
+)
+
+// mergeNewestFirst k-way merges per-author post lists into one list ordered by (created_at, id) descending,
+// stopping after limit posts. Each input list is sorted in place first.
+func mergeNewestFirst(lists [][]*models.PostDetailed, limit int) []*models.PostDetailed {
+	h := make(postHeap, 0, len(lists))
+	for _, list := range lists {
+		if len(list) == 0 {
+			continue
+		}
+		sort.SliceStable(list, func(i, j int) bool { return newer(list[i], list[j]) })
+		h = append(h, list)
+	}
+	heap.Init(&h)
+
+	merged := make([]*models.PostDetailed, 0, limit)
+	for h.Len() > 0 && len(merged) < limit {
+		list := h[0]
+		merged = append(merged, list[0])
+		if len(list) == 1 {
+			heap.Pop(&h)
+			continue
+		}
+		h[0] = list[1:]
+		heap.Fix(&h, 0)
+	}
+	return merged
+}
+
+func newer(a, b *models.PostDetailed) bool {
+	if !a.Post.CreatedAt.Equal(b.Post.CreatedAt) {
+		return a.Post.CreatedAt.After(b.Post.CreatedAt)
+	}
+	return a.Post.ID > b.Post.ID
+}
+
+// postHeap is a max-heap of non-empty post lists keyed by their head post.
+type postHeap [][]*models.PostDetailed
+
+func (h postHeap) Len() int           { return len(h) }
+func (h postHeap) Less(i, j int) bool { return newer(h[i][0], h[j][0]) }
+func (h postHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *postHeap) Push(x any) { *h = append(*h, x.([]*models.PostDetailed)) }
+
+func (h *postHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
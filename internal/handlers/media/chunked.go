@@ -0,0 +1,290 @@
+package media_handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"pinstack-api-gateway/internal/media"
+	"pinstack-api-gateway/internal/middlewares"
+	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/utils"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+type CreateUploadRequest struct {
+	Type string `json:"type" validate:"required,oneof=image video"`
+	Size int64  `json:"size" validate:"required,gt=0"`
+}
+
+type UploadResponse struct {
+	UploadID  string `json:"upload_id"`
+	Type      string `json:"type"`
+	Size      int64  `json:"size"`
+	Offset    int64  `json:"offset"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+func uploadResponse(u media.Upload) UploadResponse {
+	return UploadResponse{
+		UploadID:  u.ID,
+		Type:      string(u.Type),
+		Size:      u.Size,
+		Offset:    u.Offset,
+		ExpiresAt: u.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// CreateUpload godoc
+// @Summary Start a resumable upload
+// @Description Open an upload session for a file of the given type and total size.
+// @Description Chunks are then sent in order with PUT /media/uploads/{upload_id}; each chunk must arrive within the server's request timeout.
+// @Description A user may keep a limited number of uploads open at a time.
+// @Tags media
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateUploadRequest true "Upload to start"
+// @Success 201 {object} UploadResponse "Upload session created"
+// @Header 201 {string} Location "URL to send chunks to"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 413 {object} map[string]string "File too large"
+// @Failure 429 {object} map[string]string "Too many open uploads"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /media/uploads [post]
+func (h *MediaHandler) CreateUpload(w http.ResponseWriter, r *http.Request) {
+	claims, err := middlewares.GetClaimsFromContext(r.Context())
+	if err != nil {
+		h.log.Debug("No user claims in context", slog.String("error", err.Error()))
+		utils.SendError(w, http.StatusUnauthorized, custom_errors.ErrUnauthenticated.Error())
+		return
+	}
+
+	var req CreateUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Debug("Failed to decode create upload request", slog.String("error", err.Error()))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrInvalidInput.Error())
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		h.log.Debug("Failed to validate create upload request", slog.String("error", err.Error()))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+		return
+	}
+
+	mediaType := models.MediaType(req.Type)
+	if req.Size > h.opts.Limits.For(mediaType) {
+		h.log.Debug("Declared upload size exceeds limit", slog.String("type", req.Type), slog.Int64("size", req.Size))
+		utils.SendError(w, http.StatusRequestEntityTooLarge, custom_errors.ErrFileTooLarge.Error())
+		return
+	}
+
+	upload, err := h.uploads.Create(claims.UserID, mediaType, req.Size)
+	if errors.Is(err, media.ErrTooManyUploads) {
+		h.log.Debug("Too many open upload sessions", slog.Int64("user_id", claims.UserID))
+		utils.SendError(w, http.StatusTooManyRequests, media.ErrTooManyUploads.Error())
+		return
+	}
+	if err != nil {
+		h.log.Error("Failed to create upload session", slog.String("error", err.Error()))
+		utils.SendError(w, http.StatusInternalServerError, custom_errors.ErrExternalServiceError.Error())
+		return
+	}
+
+	w.Header().Set("Location", strings.TrimSuffix(r.URL.Path, "/")+"/"+upload.ID)
+	utils.Send(w, http.StatusCreated, uploadResponse(upload))
+}
+
+// GetUpload godoc
+// @Summary Get resumable upload status
+// @Description Report how many bytes of an upload have been received, so an interrupted client can resume from offset.
+// @Tags media
+// @Produce json
+// @Security BearerAuth
+// @Param upload_id path string true "Upload ID"
+// @Success 200 {object} UploadResponse "Upload status"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Upload not found"
+// @Router /media/uploads/{upload_id} [get]
+func (h *MediaHandler) GetUpload(w http.ResponseWriter, r *http.Request) {
+	claims, err := middlewares.GetClaimsFromContext(r.Context())
+	if err != nil {
+		h.log.Debug("No user claims in context", slog.String("error", err.Error()))
+		utils.SendError(w, http.StatusUnauthorized, custom_errors.ErrUnauthenticated.Error())
+		return
+	}
+
+	upload, err := h.uploads.Get(claims.UserID, chi.URLParam(r, "upload_id"))
+	if err != nil {
+		utils.SendError(w, http.StatusNotFound, media.ErrUploadNotFound.Error())
+		return
+	}
+
+	utils.Send(w, http.StatusOK, uploadResponse(upload))
+}
+
+// UploadChunk godoc
+// @Summary Upload a chunk
+// @Description Append a chunk to a resumable upload. Content-Range must start at the upload's current offset.
+// @Description Intermediate chunks return the upload status; the final chunk stores the file and returns the media.
+// @Tags media
+// @Accept application/octet-stream
+// @Produce json
+// @Security BearerAuth
+// @Param upload_id path string true "Upload ID"
+// @Param Content-Range header string true "Chunk position, e.g. bytes 0-1048575/5242880"
+// @Success 200 {object} UploadResponse "Chunk received"
+// @Success 201 {object} MediaResponse "Upload complete"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Upload not found"
+// @Failure 409 {object} map[string]string "Chunk does not start at the current offset"
+// @Failure 413 {object} map[string]string "Chunk or file too large"
+// @Failure 415 {object} map[string]string "Unsupported media type"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /media/uploads/{upload_id} [put]
+func (h *MediaHandler) UploadChunk(w http.ResponseWriter, r *http.Request) {
+	claims, err := middlewares.GetClaimsFromContext(r.Context())
+	if err != nil {
+		h.log.Debug("No user claims in context", slog.String("error", err.Error()))
+		utils.SendError(w, http.StatusUnauthorized, custom_errors.ErrUnauthenticated.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "upload_id")
+	start, end, total, ok := parseContentRange(r.Header.Get("Content-Range"))
+	if !ok {
+		h.log.Debug("Invalid Content-Range", slog.String("content_range", r.Header.Get("Content-Range")))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrInvalidInput.Error())
+		return
+	}
+	length := end - start + 1
+	if length > h.opts.MaxChunkSize {
+		utils.SendError(w, http.StatusRequestEntityTooLarge, custom_errors.ErrFileTooLarge.Error())
+		return
+	}
+
+	current, err := h.uploads.Get(claims.UserID, id)
+	if err != nil {
+		utils.SendError(w, http.StatusNotFound, media.ErrUploadNotFound.Error())
+		return
+	}
+	if total != current.Size {
+		h.log.Debug("Content-Range total does not match upload size", slog.Int64("total", total), slog.Int64("size", current.Size))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+		return
+	}
+
+	upload, err := h.uploads.Append(claims.UserID, id, start, http.MaxBytesReader(w, r.Body, length), length)
+	if err != nil {
+		switch {
+		case errors.Is(err, media.ErrUploadNotFound):
+			utils.SendError(w, http.StatusNotFound, media.ErrUploadNotFound.Error())
+		case errors.Is(err, media.ErrOffsetMismatch), errors.Is(err, media.ErrUploadBusy):
+			w.Header().Set("Upload-Offset", strconv.FormatInt(current.Offset, 10))
+			utils.SendError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, media.ErrChunkOverflow):
+			utils.SendError(w, http.StatusBadRequest, media.ErrChunkOverflow.Error())
+		default:
+			h.log.Debug("Failed to receive chunk", slog.String("upload_id", id), slog.String("error", err.Error()))
+			w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+			h.sendReadError(w, err)
+		}
+		return
+	}
+	if !upload.Complete() {
+		w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+		utils.Send(w, http.StatusOK, uploadResponse(upload))
+		return
+	}
+
+	// The session is finished whether or not the file is accepted.
+	defer func() { _ = h.uploads.Remove(claims.UserID, id) }()
+
+	file, err := h.uploads.Open(claims.UserID, id)
+	if err != nil {
+		h.sendSaveError(w, err)
+		return
+	}
+	defer file.Close()
+
+	resp, err := h.save(r.Context(), claims.UserID, file, upload.Size, upload.Type)
+	if err != nil {
+		h.log.Debug("Media upload rejected", slog.String("upload_id", id), slog.String("error", err.Error()))
+		h.sendSaveError(w, err)
+		return
+	}
+
+	utils.Send(w, http.StatusCreated, resp)
+}
+
+// CancelUpload godoc
+// @Summary Cancel a resumable upload
+// @Description Discard an upload session and the data received so far.
+// @Tags media
+// @Produce json
+// @Security BearerAuth
+// @Param upload_id path string true "Upload ID"
+// @Success 200 {object} nil "Upload cancelled"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Upload not found"
+// @Failure 409 {object} map[string]string "Upload is receiving a chunk"
+// @Router /media/uploads/{upload_id} [delete]
+func (h *MediaHandler) CancelUpload(w http.ResponseWriter, r *http.Request) {
+	claims, err := middlewares.GetClaimsFromContext(r.Context())
+	if err != nil {
+		h.log.Debug("No user claims in context", slog.String("error", err.Error()))
+		utils.SendError(w, http.StatusUnauthorized, custom_errors.ErrUnauthenticated.Error())
+		return
+	}
+
+	if err := h.uploads.Remove(claims.UserID, chi.URLParam(r, "upload_id")); err != nil {
+		switch {
+		case errors.Is(err, media.ErrUploadBusy):
+			utils.SendError(w, http.StatusConflict, media.ErrUploadBusy.Error())
+		default:
+			utils.SendError(w, http.StatusNotFound, media.ErrUploadNotFound.Error())
+		}
+		return
+	}
+
+	utils.Send(w, http.StatusOK, nil)
+}
+
+// parseContentRange parses "bytes start-end/total" as sent with a chunk.
+func parseContentRange(header string) (start, end, total int64, ok bool) {
+	spec, found := strings.CutPrefix(header, "bytes ")
+	if !found {
+		return 0, 0, 0, false
+	}
+	rng, totalStr, found := strings.Cut(spec, "/")
+	if !found {
+		return 0, 0, 0, false
+	}
+	startStr, endStr, found := strings.Cut(rng, "-")
+	if !found {
+		return 0, 0, 0, false
+	}
+	var err error
+	if start, err = strconv.ParseInt(startStr, 10, 64); err != nil {
+		return 0, 0, 0, false
+	}
+	if end, err = strconv.ParseInt(endStr, 10, 64); err != nil {
+		return 0, 0, 0, false
+	}
+	if total, err = strconv.ParseInt(totalStr, 10, 64); err != nil {
+		return 0, 0, 0, false
+	}
+	if start < 0 || end < start || total <= end {
+		return 0, 0, 0, false
+	}
+	return start, end, total, true
+}
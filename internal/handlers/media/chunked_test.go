@@ -0,0 +1,33 @@
+package media_handler
+
+import "testing"
+
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		header            string
+		start, end, total int64
+		ok                bool
+	}{
+		{header: "bytes 0-99/1000", start: 0, end: 99, total: 1000, ok: true},
+		{header: "bytes 900-999/1000", start: 900, end: 999, total: 1000, ok: true},
+		{header: "bytes 0-0/1", start: 0, end: 0, total: 1, ok: true},
+		{header: ""},
+		{header: "0-99/1000"},
+		{header: "bytes 0-99"},
+		{header: "bytes 0-99/*"},
+		{header: "bytes */1000"},
+		{header: "bytes 99/1000"},
+		{header: "bytes -1-99/1000"},
+		{header: "bytes 100-99/1000"},
+		{header: "bytes 0-1000/1000"},
+		{header: "bytes a-99/1000"},
+		{header: "items 0-99/1000"},
+	}
+	for _, tt := range tests {
+		start, end, total, ok := parseContentRange(tt.header)
+		if ok != tt.ok || start != tt.start || end != tt.end || total != tt.total {
+			t.Errorf("parseContentRange(%q) = %d, %d, %d, %v; want %d, %d, %d, %v",
+				tt.header, start, end, total, ok, tt.start, tt.end, tt.total, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package media_handler
+
+import (
+	"pinstack-api-gateway/internal/logger"
+	"pinstack-api-gateway/internal/media"
+)
+
+type Options struct {
+	Limits media.Limits
+	// MaxChunkSize caps the body of a single resumable upload chunk.
+	MaxChunkSize int64
+	// SpoolDir holds multipart uploads while they are sniffed and size checked.
+	SpoolDir string
+	Images   media.ImageOptions
+}
+
+type MediaHandler struct {
+	store   media.MediaStore
+	uploads *media.Uploads
+	urls    *media.URLResolver
+	opts    Options
+	log     *logger.Logger
+}
+
+func NewMediaHandler(store media.MediaStore, uploads *media.Uploads, urls *media.URLResolver, opts Options, log *logger.Logger) *MediaHandler {
+	if opts.MaxChunkSize <= 0 {
+		opts.MaxChunkSize = 8 << 20
+	}
+	return &MediaHandler{
+		store:   store,
+		uploads: uploads,
+		urls:    urls,
+		opts:    opts,
+		log:     log,
+	}
+}
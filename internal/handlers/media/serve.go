@@ -0,0 +1,73 @@
+package media_handler
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"pinstack-api-gateway/internal/media"
+	"pinstack-api-gateway/internal/utils"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// immutableMaxAge is how long unsigned media may be cached. Keys are never reused for different content.
+const immutableMaxAge = 365 * 24 * time.Hour
+
+// Serve streams a stored media object. When media URLs are signed, the request must carry
+// a valid, unexpired signature for the key. Seekable objects are served with support for
+// range requests, which video players rely on, and conditional requests.
+func (h *MediaHandler) Serve(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "*")
+	if err := h.urls.Verify(key, r.URL.Query()); err != nil {
+		h.log.Debug("Rejected media request", slog.String("key", key), slog.String("error", err.Error()))
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	body, info, err := h.store.Get(r.Context(), key)
+	if err != nil {
+		switch {
+		case errors.Is(err, media.ErrObjectNotFound), errors.Is(err, media.ErrInvalidKey):
+			http.NotFound(w, r)
+		default:
+			h.log.Error("Failed to read media", slog.String("key", key), slog.String("error", err.Error()))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+	defer body.Close()
+
+	if info.ContentType != "" {
+		w.Header().Set("Content-Type", info.ContentType)
+	}
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("ETag", utils.ResourceETag("media", key))
+	if expires := h.urls.Expires(r.URL.Query()); !expires.IsZero() {
+		maxAge := max(0, int(time.Until(expires).Seconds()))
+		w.Header().Set("Cache-Control", "private, max-age="+strconv.Itoa(maxAge)+", immutable")
+	} else {
+		w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(immutableMaxAge.Seconds()))+", immutable")
+	}
+
+	if seeker, ok := body.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, "", info.LastModified, seeker)
+		return
+	}
+
+	if info.Size >= 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(info.Size, 10))
+	}
+	if !info.LastModified.IsZero() {
+		w.Header().Set("Last-Modified", info.LastModified.UTC().Format(http.TimeFormat))
+	}
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodHead {
+		return
+	}
+	if _, err := io.Copy(w, body); err != nil {
+		h.log.Debug("Failed to stream media", slog.String("key", key), slog.String("error", err.Error()))
+	}
+}
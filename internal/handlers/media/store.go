@@ -0,0 +1,123 @@
+package media_handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"pinstack-api-gateway/internal/media"
+	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/utils"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+type MediaResponse struct {
+	Key         string                `json:"key"`
+	URL         string                `json:"url"`
+	Type        string                `json:"type"`
+	ContentType string                `json:"content_type"`
+	Size        int64                 `json:"size"`
+	Width       int                   `json:"width,omitempty"`
+	Height      int                   `json:"height,omitempty"`
+	Variants    *models.MediaVariants `json:"variants,omitempty"`
+}
+
+// save sniffs a fully received file, checks it against the declared type and the
+// size limit of its actual type, and hands it to the media store. Images are
+// processed into variants first.
+func (h *MediaHandler) save(ctx context.Context, userID int64, file *os.File, size int64, declared models.MediaType) (*MediaResponse, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	contentType, mediaType, err := media.Sniff(head[:n])
+	if err != nil {
+		return nil, err
+	}
+	if declared != "" && declared != mediaType {
+		return nil, media.ErrTypeMismatch
+	}
+	if size > h.opts.Limits.For(mediaType) {
+		return nil, custom_errors.ErrFileTooLarge
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if mediaType == models.MediaTypeImage {
+		return h.saveImage(ctx, userID, file, contentType)
+	}
+
+	key, err := media.NewKey(userID, contentType)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.store.Put(ctx, key, file, size, contentType); err != nil {
+		return nil, err
+	}
+
+	return &MediaResponse{
+		Key:         key,
+		URL:         h.store.URL(key),
+		Type:        string(mediaType),
+		ContentType: contentType,
+		Size:        size,
+	}, nil
+}
+
+// saveImage stores the processed variants of an image in place of the original upload.
+// The full variant is the canonical URL; the others are derived from it by the URL resolver.
+func (h *MediaHandler) saveImage(ctx context.Context, userID int64, file io.ReadSeeker, contentType string) (*MediaResponse, error) {
+	processed, err := media.ProcessImage(file, contentType, h.opts.Images)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := media.NewKey(userID, processed.ContentType)
+	if err != nil {
+		return nil, err
+	}
+	for variant, img := range processed.Variants {
+		variantKey := media.VariantKey(key, variant)
+		if err := h.store.Put(ctx, variantKey, bytes.NewReader(img.Data), int64(len(img.Data)), processed.ContentType); err != nil {
+			return nil, err
+		}
+	}
+
+	full := processed.Variants[media.VariantFull]
+	fullKey := media.VariantKey(key, media.VariantFull)
+	return &MediaResponse{
+		Key:         fullKey,
+		URL:         h.store.URL(fullKey),
+		Type:        string(models.MediaTypeImage),
+		ContentType: processed.ContentType,
+		Size:        int64(len(full.Data)),
+		Width:       full.Width,
+		Height:      full.Height,
+		Variants:    h.urls.Variants(h.store.URL(fullKey)),
+	}, nil
+}
+
+// sendSaveError maps a failure from save to a response.
+func (h *MediaHandler) sendSaveError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, media.ErrUnsupportedType):
+		utils.SendError(w, http.StatusUnsupportedMediaType, media.ErrUnsupportedType.Error())
+	case errors.Is(err, media.ErrInvalidImage):
+		utils.SendError(w, http.StatusBadRequest, media.ErrInvalidImage.Error())
+	case errors.Is(err, media.ErrImageTooLarge):
+		utils.SendError(w, http.StatusRequestEntityTooLarge, media.ErrImageTooLarge.Error())
+	case errors.Is(err, media.ErrTypeMismatch):
+		utils.SendError(w, http.StatusBadRequest, media.ErrTypeMismatch.Error())
+	case errors.Is(err, custom_errors.ErrFileTooLarge):
+		utils.SendError(w, http.StatusRequestEntityTooLarge, custom_errors.ErrFileTooLarge.Error())
+	default:
+		h.log.Error("Failed to store media", slog.String("error", err.Error()))
+		utils.SendError(w, http.StatusInternalServerError, custom_errors.ErrExternalServiceError.Error())
+	}
+}
@@ -0,0 +1,149 @@
+package media_handler
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"pinstack-api-gateway/internal/middlewares"
+	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/utils"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+// multipartOverhead allows for part headers and boundaries around the file.
+const multipartOverhead = 64 << 10
+
+// Upload godoc
+// @Summary Upload media
+// @Description Upload an image or video as multipart/form-data in the "file" field.
+// @Description The file type is detected from its content; an optional "type" field (image or video) must agree with it.
+// @Description Images are upright-rotated, stripped of metadata and re-encoded as thumbnail, medium and full variants;
+// @Description url is the full variant. The returned url is stable and can be used in a post's media_items or as an avatar_url;
+// @Description when media URLs are signed, variants carry signed URLs for immediate display.
+// @Description The whole request must arrive within the server's request timeout, so this endpoint is meant for small files;
+// @Description send large files, especially videos, as a resumable upload through POST /media/uploads.
+// @Tags media
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "Media file"
+// @Param type formData string false "Expected media type" Enums(image, video)
+// @Success 201 {object} MediaResponse "Media uploaded successfully"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 413 {object} map[string]string "File too large"
+// @Failure 415 {object} map[string]string "Unsupported media type"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /media [post]
+func (h *MediaHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	claims, err := middlewares.GetClaimsFromContext(r.Context())
+	if err != nil {
+		h.log.Debug("No user claims in context", slog.String("error", err.Error()))
+		utils.SendError(w, http.StatusUnauthorized, custom_errors.ErrUnauthenticated.Error())
+		return
+	}
+
+	maxSize := h.opts.Limits.Max()
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+multipartOverhead)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		h.log.Debug("Request is not multipart", slog.String("error", err.Error()))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrInvalidInput.Error())
+		return
+	}
+
+	var (
+		declared models.MediaType
+		file     *os.File
+		size     int64
+	)
+	defer func() {
+		if file != nil {
+			_ = file.Close()
+			_ = os.Remove(file.Name())
+		}
+	}()
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			h.sendReadError(w, err)
+			return
+		}
+
+		switch part.FormName() {
+		case "type":
+			value, err := io.ReadAll(io.LimitReader(part, 16))
+			if err != nil {
+				h.sendReadError(w, err)
+				return
+			}
+			declared = models.MediaType(value)
+			if declared != models.MediaTypeImage && declared != models.MediaTypeVideo {
+				h.log.Debug("Invalid media type field", slog.String("type", string(value)))
+				utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+				return
+			}
+		case "file":
+			if file != nil {
+				h.log.Debug("Multiple files in media upload")
+				utils.SendError(w, http.StatusBadRequest, custom_errors.ErrInvalidInput.Error())
+				return
+			}
+			file, err = os.CreateTemp(h.opts.SpoolDir, "media-*")
+			if err != nil {
+				h.log.Error("Failed to create spool file", slog.String("error", err.Error()))
+				utils.SendError(w, http.StatusInternalServerError, custom_errors.ErrExternalServiceError.Error())
+				return
+			}
+			size, err = io.Copy(file, io.LimitReader(part, maxSize+1))
+			if err != nil {
+				h.sendReadError(w, err)
+				return
+			}
+			if size > maxSize {
+				h.log.Debug("Media upload exceeds size limit", slog.Int64("limit", maxSize))
+				utils.SendError(w, http.StatusRequestEntityTooLarge, custom_errors.ErrFileTooLarge.Error())
+				return
+			}
+		}
+		_ = part.Close()
+	}
+
+	if file == nil || size == 0 {
+		h.log.Debug("Media upload without file")
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+		return
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		h.sendSaveError(w, err)
+		return
+	}
+
+	resp, err := h.save(r.Context(), claims.UserID, file, size, declared)
+	if err != nil {
+		h.log.Debug("Media upload rejected", slog.Int64("user_id", claims.UserID), slog.String("error", err.Error()))
+		h.sendSaveError(w, err)
+		return
+	}
+
+	utils.Send(w, http.StatusCreated, resp)
+}
+
+// sendReadError maps a failure reading the request body to a response.
+func (h *MediaHandler) sendReadError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		h.log.Debug("Request body exceeds size limit", slog.Int64("limit", maxBytesErr.Limit))
+		utils.SendError(w, http.StatusRequestEntityTooLarge, custom_errors.ErrFileTooLarge.Error())
+		return
+	}
+	h.log.Debug("Failed to read upload", slog.String("error", err.Error()))
+	utils.SendError(w, http.StatusBadRequest, custom_errors.ErrInvalidInput.Error())
+}
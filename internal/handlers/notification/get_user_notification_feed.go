@@ -6,8 +6,8 @@ import (
 	"net/http"
 	"pinstack-api-gateway/internal/middlewares"
 	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/pagination"
 	"pinstack-api-gateway/internal/utils"
-	"strconv"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -16,18 +16,12 @@ import (
 // GetUserNotificationFeedSwaggerResponse is the Swagger response structure for GetUserNotificationFeed
 type GetUserNotificationFeedSwaggerResponse struct {
 	Notifications []*models.NotificationSwagger `json:"notifications"`
-	Total         int                           `json:"total"`
-	Page          int                           `json:"page"`
-	Limit         int                           `json:"limit"`
-	TotalPages    int                           `json:"total_pages"`
+	pagination.Meta
 }
 
 type GetUserNotificationFeedResponse struct {
 	Notifications []*models.Notification `json:"notifications"`
-	Total         int                    `json:"total"`
-	Page          int                    `json:"page"`
-	Limit         int                    `json:"limit"`
-	TotalPages    int                    `json:"total_pages"`
+	pagination.Meta
 }
 
 // GetUserNotificationFeed godoc
@@ -37,37 +31,21 @@ type GetUserNotificationFeedResponse struct {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param page query int false "Page number (default: 1)"
-// @Param limit query int false "Items per page (default: 10)"
+// @Param page query int false "Page number, starting at 1" default(1)
+// @Param limit query int false "Page size (max 100)" default(20)
 // @Success 200 {object} GetUserNotificationFeedSwaggerResponse "User notification feed"
+// @Header 200 {string} Link "RFC 8288 pagination links"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /notification/feed [get]
 func (h *NotificationHandler) GetUserNotificationFeed(w http.ResponseWriter, r *http.Request) {
-	pageStr := r.URL.Query().Get("page")
-	limitStr := r.URL.Query().Get("limit")
-
-	var page, limit int32 = 1, 10
-
-	if pageStr != "" {
-		pageInt, err := strconv.ParseInt(pageStr, 10, 32)
-		if err != nil || pageInt < 1 {
-			h.log.Debug("Invalid page parameter", slog.String("page", pageStr))
-			utils.SendError(w, http.StatusBadRequest, custom_errors.ErrInvalidInput.Error())
-			return
-		}
-		page = int32(pageInt)
-	}
-
-	if limitStr != "" {
-		limitInt, err := strconv.ParseInt(limitStr, 10, 32)
-		if err != nil || limitInt < 1 || limitInt > 100 {
-			h.log.Debug("Invalid limit parameter", slog.String("limit", limitStr))
-			utils.SendError(w, http.StatusBadRequest, custom_errors.ErrInvalidInput.Error())
-			return
-		}
-		limit = int32(limitInt)
+	params, err := pagination.Parse(r.URL.Query())
+	if err != nil || !params.PageAligned() {
+		h.log.Debug("Invalid pagination parameters", slog.String("query", r.URL.RawQuery))
+		utils.SendError(w, http.StatusBadRequest, pagination.ErrInvalidPagination.Error())
+		return
 	}
+	page, limit := int32(params.Page), int32(params.Limit)
 
 	claims, err := middlewares.GetClaimsFromContext(r.Context())
 	if err != nil {
@@ -104,28 +82,19 @@ func (h *NotificationHandler) GetUserNotificationFeed(w http.ResponseWriter, r *
 
 	h.log.Debug("notifications from client", slog.Int("notifications_count", len(notifications)), slog.Int("total", int(total)))
 
-	var totalPages int32
-	if total%limit == 0 {
-		totalPages = total / limit
-	} else {
-		totalPages = (total / limit) + 1
-	}
-
 	response := GetUserNotificationFeedResponse{
 		Notifications: notifications,
-		Total:         int(total),
-		Page:          int(page),
-		Limit:         int(limit),
-		TotalPages:    int(totalPages),
+		Meta:          params.Meta(int64(total), len(notifications)),
 	}
 
 	h.log.Debug("Get user notification feed response",
 		slog.Int("notifications_count", len(response.Notifications)),
-		slog.Int("total", response.Total),
+		slog.Int64("total", response.Total),
 		slog.Int("page", response.Page),
 		slog.Int("limit", response.Limit),
 		slog.Int("total_pages", response.TotalPages),
 	)
 
+	params.SetLinkHeader(w, r, response.Meta)
 	utils.Send(w, http.StatusOK, response)
 }
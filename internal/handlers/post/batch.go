@@ -0,0 +1,177 @@
+package post_handler
+
+import (
+	"context"
+	"errors"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"log/slog"
+	"net/http"
+	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/utils"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxBatchIDs  = 100
+	batchWorkers = 10
+)
+
+type BatchPostsResponse struct {
+	// Posts are in the order their IDs were requested.
+	Posts []ListPostItem `json:"posts"`
+	// Missing lists requested IDs for which no post exists.
+	Missing []int64 `json:"missing"`
+	// Failed lists requested IDs that could not be loaded because of a transient error; they may be retried.
+	Failed []int64 `json:"failed"`
+}
+
+// Batch godoc
+// @Summary Get posts by IDs
+// @Description Get up to 100 posts in one request. Posts are returned in the order requested, duplicates once;
+// @Description IDs of posts that do not exist are reported in missing and IDs that could not be loaded right now in failed,
+// @Description instead of failing the request. Posts whose author could not be loaded are returned without author.
+// @Tags posts
+// @Produce json
+// @Param ids query string true "Comma separated post IDs (max 100)"
+// @Param render query string false "Set to html to include content_html rendered from Markdown" Enums(html)
+// @Param fields query string false "Comma separated fields to return for each post, e.g. title,author.username"
+// @Param expand query string false "Comma separated expansions (author, media, tags); all by default, none when empty"
+// @Success 200 {object} BatchPostsResponse "Posts"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Router /posts [get]
+func (h *PostHandler) Batch(w http.ResponseWriter, r *http.Request) {
+	ids, err := parseIDs(r.URL.Query()["ids"])
+	if err != nil || len(ids) == 0 || len(ids) > maxBatchIDs {
+		h.log.Debug("Invalid batch post ids", slog.Any("ids", r.URL.Query()["ids"]))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+		return
+	}
+
+	renderHTML, ok := renderHTMLRequested(r)
+	if !ok {
+		h.log.Debug("Invalid render parameter", slog.String("render", r.URL.Query().Get("render")))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+		return
+	}
+
+	sel, err := parsePostSelection(r.URL.Query())
+	if err != nil {
+		h.log.Debug("Invalid fields or expand parameter", slog.String("query", r.URL.RawQuery))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+		return
+	}
+
+	posts, errs := h.getPosts(r.Context(), ids)
+
+	var authors map[int64]*models.User
+	if sel.Expands(expandAuthor) {
+		authorIDs := make([]int64, 0, len(posts))
+		for _, p := range posts {
+			if p != nil {
+				authorIDs = append(authorIDs, p.Post.AuthorID)
+			}
+		}
+		authors = h.getAuthors(r.Context(), authorIDs)
+	}
+
+	resp := BatchPostsResponse{
+		Posts:   make([]ListPostItem, 0, len(posts)),
+		Missing: make([]int64, 0),
+		Failed:  make([]int64, 0),
+	}
+	for i, p := range posts {
+		switch {
+		case errors.Is(errs[i], custom_errors.ErrPostNotFound):
+			resp.Missing = append(resp.Missing, ids[i])
+			continue
+		case errs[i] != nil:
+			resp.Failed = append(resp.Failed, ids[i])
+			continue
+		}
+		resp.Posts = append(resp.Posts, h.listItem(p, authors[p.Post.AuthorID], renderHTML, sel))
+	}
+
+	utils.SendSelected(w, http.StatusOK, sel, resp, "posts")
+}
+
+// parseIDs reads IDs from comma separated and repeated parameters, deduplicated in order of first appearance.
+func parseIDs(values []string) ([]int64, error) {
+	seen := make(map[int64]struct{})
+	ids := make([]int64, 0)
+	for _, value := range values {
+		for _, s := range strings.Split(value, ",") {
+			s = strings.TrimSpace(s)
+			if s == "" {
+				continue
+			}
+			id, err := strconv.ParseInt(s, 10, 64)
+			if err != nil || id <= 0 {
+				return nil, custom_errors.ErrInvalidInput
+			}
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// getPosts fetches posts concurrently. Both results are aligned with ids: a post, or the error that kept it
+// from being loaded, so one failed lookup does not fail the whole batch.
+func (h *PostHandler) getPosts(ctx context.Context, ids []int64) ([]*models.PostDetailed, []error) {
+	posts := make([]*models.PostDetailed, len(ids))
+	errs := utils.FanOut(ctx, len(ids), batchWorkers, func(ctx context.Context, i int) error {
+		post, err := h.postClient.GetPostByID(ctx, ids[i])
+		if err != nil {
+			return err
+		}
+		posts[i] = post
+		return nil
+	})
+	for i, err := range errs {
+		if err != nil && !errors.Is(err, custom_errors.ErrPostNotFound) {
+			h.log.Warn("Batch get post failed", slog.Int64("post_id", ids[i]), slog.String("error", err.Error()))
+		}
+	}
+	return posts, errs
+}
+
+// getAuthors looks up each distinct author once, concurrently. Authors that no longer exist are replaced
+// by the placeholder author; authors that could not be loaded are left out, so their posts have no author.
+func (h *PostHandler) getAuthors(ctx context.Context, ids []int64) map[int64]*models.User {
+	unique := make([]int64, 0, len(ids))
+	seen := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			unique = append(unique, id)
+		}
+	}
+
+	found := make([]*models.User, len(unique))
+	errs := utils.FanOut(ctx, len(unique), batchWorkers, func(ctx context.Context, i int) error {
+		author, err := h.userClient.GetUser(ctx, unique[i])
+		if err != nil {
+			return err
+		}
+		found[i] = author
+		return nil
+	})
+
+	authors := make(map[int64]*models.User, len(unique))
+	for i, id := range unique {
+		switch err := errs[i]; {
+		case err == nil:
+			authors[id] = found[i]
+		case errors.Is(err, custom_errors.ErrUserNotFound):
+			h.log.Warn("author not found, using placeholder", slog.Int64("authorID", id))
+			authors[id] = utils.GenerateUnknownAuthor()
+		default:
+			h.log.Warn("Batch get author failed", slog.Int64("authorID", id), slog.String("error", err.Error()))
+		}
+	}
+	return authors
+}
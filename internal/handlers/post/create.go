@@ -9,8 +9,11 @@ import (
 	"google.golang.org/grpc/status"
 	"log/slog"
 	"net/http"
+	"pinstack-api-gateway/internal/content"
 	"pinstack-api-gateway/internal/middlewares"
 	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/moderation"
+	"pinstack-api-gateway/internal/userview"
 	"pinstack-api-gateway/internal/utils"
 )
 
@@ -28,26 +31,25 @@ type MediaItemInput struct {
 }
 
 type CreatePostResponse struct {
-	ID              int64               `json:"id"`
-	Title           string              `json:"title"`
-	Content         *string             `json:"content,omitempty"`
-	CreatedAt       string              `json:"created_at"`
-	UpdatedAt       string              `json:"updated_at"`
-	AuthorID        int64               `json:"author_id"`
-	AuthorUsername  string              `json:"author_username"`
-	AuthorEmail     string              `json:"author_email"`
-	AuthorFullName  *string             `json:"author_full_name,omitempty"`
-	AuthorBio       *string             `json:"author_bio,omitempty"`
-	AuthorAvatarURL *string             `json:"author_avatar_url,omitempty"`
-	Media           []PostMediaResponse `json:"media,omitempty"`
-	Tags            []TagResponse       `json:"tags,omitempty"`
+	ID          int64               `json:"id"`
+	Title       string              `json:"title"`
+	Content     *string             `json:"content,omitempty"`
+	ContentHTML *string             `json:"content_html,omitempty"`
+	CreatedAt   string              `json:"created_at"`
+	UpdatedAt   string              `json:"updated_at"`
+	AuthorID    int64               `json:"author_id"`
+	Author      *userview.Author    `json:"author,omitempty"`
+	Media       []PostMediaResponse `json:"media,omitempty"`
+	Tags        []TagResponse       `json:"tags,omitempty"`
+	Mentions    []MentionResponse   `json:"mentions,omitempty"`
 }
 
 type PostMediaResponse struct {
-	ID       int64  `json:"id"`
-	URL      string `json:"url"`
-	Type     string `json:"type"`
-	Position int32  `json:"position"`
+	ID       int64                 `json:"id"`
+	URL      string                `json:"url"`
+	Type     string                `json:"type"`
+	Position int32                 `json:"position"`
+	Variants *models.MediaVariants `json:"variants,omitempty"`
 }
 
 type TagResponse struct {
@@ -58,14 +60,19 @@ type TagResponse struct {
 // Create godoc
 // @Summary Create a new post
 // @Description Create a new post with title, content, tags and media
+// @Description Users @mentioned in the content are resolved and notified, and #hashtags in the content are added to the tags.
+// @Description Tags are lower-cased and must be at most 50 letters, digits, underscores or + . - # characters, including a letter.
+// @Description The title, content, tags and media URLs are checked against the moderation rules before the post is stored.
 // @Tags posts
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body CreatePostRequest true "Post creation data"
+// @Param render query string false "Set to html to include content_html rendered from Markdown" Enums(html)
 // @Success 201 {object} CreatePostResponse "Post created successfully"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 422 {object} ModerationRejectedResponse "Content rejected by moderation"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /posts [post]
 func (h *PostHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -84,6 +91,13 @@ func (h *PostHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 	h.log.Debug("requested model", slog.Any("model", req))
 
+	renderHTML, ok := renderHTMLRequested(r)
+	if !ok {
+		h.log.Debug("Invalid render parameter", slog.String("render", r.URL.Query().Get("render")))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+		return
+	}
+
 	validate := validator.New()
 	if err := validate.Struct(req); err != nil {
 		h.log.Debug("Failed to validate create post request", slog.String("error", err.Error()))
@@ -91,11 +105,29 @@ func (h *PostHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	tags, err := content.MergeTags(req.Tags, req.Content)
+	if err != nil {
+		h.log.Debug("Invalid post tags", slog.Any("tags", req.Tags), slog.String("error", err.Error()))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+		return
+	}
+
+	if !h.moderate(w, r, &moderation.Content{
+		Operation: moderation.OperationCreatePost,
+		UserID:    claims.UserID,
+		Title:     req.Title,
+		Text:      stringValue(req.Content),
+		Tags:      tags,
+		MediaURLs: mediaItemURLs(req.MediaItems),
+	}) {
+		return
+	}
+
 	modelReq := &models.CreatePostDTO{
 		AuthorID: claims.UserID,
 		Title:    req.Title,
 		Content:  req.Content,
-		Tags:     req.Tags,
+		Tags:     tags,
 	}
 	if len(req.MediaItems) > 0 {
 		modelReq.MediaItems = make([]*models.PostMediaInput, 0, len(req.MediaItems))
@@ -159,20 +191,21 @@ func (h *PostHandler) Create(w http.ResponseWriter, r *http.Request) {
 		AuthorID:  post.Post.AuthorID,
 	}
 
-	resp.AuthorEmail = author.Email
-	resp.AuthorAvatarURL = author.AvatarURL
-	resp.AuthorBio = author.Bio
-	resp.AuthorFullName = author.FullName
-	resp.AuthorUsername = author.Username
+	if renderHTML {
+		resp.ContentHTML = h.contentHTML(post.Post)
+	}
+
+	resp.Author = userview.NewAuthor(author, h.avatarURL(author))
 
 	if len(post.Media) > 0 {
 		resp.Media = make([]PostMediaResponse, len(post.Media))
 		for i, m := range post.Media {
 			resp.Media[i] = PostMediaResponse{
 				ID:       m.ID,
-				URL:      m.URL,
+				URL:      h.mediaURLs.Public(m.URL),
 				Type:     string(m.Type),
 				Position: m.Position,
+				Variants: h.mediaVariants(m),
 			}
 		}
 	}
@@ -185,5 +218,10 @@ func (h *PostHandler) Create(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	}
+
+	mentions := h.resolveMentions(r.Context(), post.Post.Content)
+	h.notifyMentions(post.Post.ID, post.Post.AuthorID, mentions, nil)
+	resp.Mentions = mentionResponses(mentions)
+
 	utils.Send(w, http.StatusCreated, resp)
 }
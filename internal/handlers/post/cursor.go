@@ -0,0 +1,189 @@
+package post_handler
+
+import (
+	"context"
+	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/pagination"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cursorTieSlack is how many extra rows are requested so posts sharing the cursor timestamp can be skipped.
+const cursorTieSlack = 10
+
+// cursorFilters returns the filters a cursor is bound to, in canonical string form.
+func cursorFilters(filters *models.PostFilters) map[string]string {
+	result := make(map[string]string)
+	if filters.AuthorID != nil {
+		result["author_id"] = strconv.FormatInt(*filters.AuthorID, 10)
+	}
+	if filters.CreatedAfter != nil {
+		result["created_after"] = filters.CreatedAfter.UTC().Format(time.RFC3339Nano)
+	}
+	if filters.CreatedBefore != nil {
+		result["created_before"] = filters.CreatedBefore.UTC().Format(time.RFC3339Nano)
+	}
+	if len(filters.TagNames) > 0 {
+		tags := append([]string(nil), filters.TagNames...)
+		sort.Strings(tags)
+		result["tags"] = strings.Join(tags, ",")
+		result["tag_mode"] = string(filters.TagMode)
+	}
+	return result
+}
+
+// postFiltersFromCursor rebuilds the list filters stored in a cursor.
+func postFiltersFromCursor(values map[string]string) (models.PostFilters, error) {
+	var filters models.PostFilters
+	if v, ok := values["author_id"]; ok {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return filters, pagination.ErrInvalidCursor
+		}
+		filters.AuthorID = &id
+	}
+	if v, ok := values["created_after"]; ok {
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return filters, pagination.ErrInvalidCursor
+		}
+		filters.CreatedAfter = &t
+	}
+	if v, ok := values["created_before"]; ok {
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return filters, pagination.ErrInvalidCursor
+		}
+		filters.CreatedBefore = &t
+	}
+	if v, ok := values["tags"]; ok {
+		filters.TagNames = parseTagNames([]string{v})
+		filters.TagMode = models.TagMatchMode(values["tag_mode"])
+		if filters.TagMode != models.TagMatchAny && filters.TagMode != models.TagMatchAll {
+			return filters, pagination.ErrInvalidCursor
+		}
+	}
+	return filters, nil
+}
+
+// listPostsByCursor fetches one keyset page relative to cursor, newest first, and the total for base.
+// The post service only knows offsets and date bounds, so the cursor position is translated into
+// a created_before / created_after bound and rows on the boundary timestamp are trimmed here.
+// Filters evaluated by the gateway are scanned once and paged in memory instead.
+func (h *PostHandler) listPostsByCursor(ctx context.Context, base models.PostFilters, cursor pagination.Cursor, limit int) (posts []*models.PostDetailed, total int64, hasNext, hasPrev bool, err error) {
+	if filteredInGateway(&base) {
+		matched, err := h.scanPosts(ctx, &base)
+		if err != nil {
+			return nil, 0, false, false, err
+		}
+		posts, hasNext, hasPrev = cursorPage(matched, cursor, limit)
+		return posts, int64(len(matched)), hasNext, hasPrev, nil
+	}
+
+	countLimit := 1
+	countFilters := base
+	countFilters.Limit = &countLimit
+	if _, total, err = h.postClient.ListPosts(ctx, &countFilters); err != nil {
+		return nil, 0, false, false, err
+	}
+
+	fetchLimit := limit + 1 + cursorTieSlack
+	filters := base
+	offset := 0
+
+	if cursor.Direction == pagination.DirectionNext {
+		before := cursor.CreatedAt.Add(time.Microsecond)
+		if filters.CreatedBefore == nil || before.Before(*filters.CreatedBefore) {
+			filters.CreatedBefore = &before
+		}
+	} else {
+		after := cursor.CreatedAt.Add(-time.Microsecond)
+		if filters.CreatedAfter == nil || after.After(*filters.CreatedAfter) {
+			filters.CreatedAfter = &after
+		}
+
+		filters.Limit = &countLimit
+		_, newer, err := h.postClient.ListPosts(ctx, &filters)
+		if err != nil {
+			return nil, 0, false, false, err
+		}
+		offset = max(0, int(newer)-fetchLimit)
+	}
+
+	filters.Offset = &offset
+	filters.Limit = &fetchLimit
+	items, _, err := h.postClient.ListPosts(ctx, &filters)
+	if err != nil {
+		return nil, 0, false, false, err
+	}
+	sortPostsNewestFirst(items)
+	posts, hasNext, hasPrev = cursorPage(items, cursor, limit)
+	if cursor.Direction == pagination.DirectionPrev && offset > 0 {
+		hasPrev = true
+	}
+	return posts, total, hasNext, hasPrev, nil
+}
+
+// cursorPage takes the page of up to limit posts next to cursor from posts sorted newest first.
+func cursorPage(posts []*models.PostDetailed, cursor pagination.Cursor, limit int) (page []*models.PostDetailed, hasNext, hasPrev bool) {
+	if cursor.Direction == pagination.DirectionNext {
+		start := sort.Search(len(posts), func(i int) bool { return isOlder(posts[i], cursor.CreatedAt, cursor.ID) })
+		end := min(start+limit, len(posts))
+		return posts[start:end], end < len(posts), true
+	}
+	end := sort.Search(len(posts), func(i int) bool { return !isNewer(posts[i], cursor.CreatedAt, cursor.ID) })
+	start := max(0, end-limit)
+	return posts[start:end], true, start > 0
+}
+
+// pageCursors encodes the cursors pointing before the first and after the last post of a page.
+func (h *PostHandler) pageCursors(posts []*models.PostDetailed, filters map[string]string, hasNext, hasPrev bool) (next, prev string, err error) {
+	if len(posts) == 0 {
+		return "", "", nil
+	}
+	if hasNext {
+		last := posts[len(posts)-1].Post
+		next, err = h.cursors.Encode(pagination.Cursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+			Direction: pagination.DirectionNext,
+			Filters:   filters,
+		})
+		if err != nil {
+			return "", "", err
+		}
+	}
+	if hasPrev {
+		first := posts[0].Post
+		prev, err = h.cursors.Encode(pagination.Cursor{
+			CreatedAt: first.CreatedAt,
+			ID:        first.ID,
+			Direction: pagination.DirectionPrev,
+			Filters:   filters,
+		})
+		if err != nil {
+			return "", "", err
+		}
+	}
+	return next, prev, nil
+}
+
+func sortPostsNewestFirst(posts []*models.PostDetailed) {
+	sort.SliceStable(posts, func(i, j int) bool {
+		a, b := posts[i].Post, posts[j].Post
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID > b.ID
+	})
+}
+
+func isOlder(p *models.PostDetailed, createdAt time.Time, id int64) bool {
+	return p.Post.CreatedAt.Before(createdAt) || (p.Post.CreatedAt.Equal(createdAt) && p.Post.ID < id)
+}
+
+func isNewer(p *models.PostDetailed, createdAt time.Time, id int64) bool {
+	return p.Post.CreatedAt.After(createdAt) || (p.Post.CreatedAt.Equal(createdAt) && p.Post.ID > id)
+}
@@ -0,0 +1,30 @@
+package post_handler
+
+import (
+	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/utils"
+	"strconv"
+	"time"
+)
+
+// postETag identifies a representation of a post version. The embedded author, if there is one,
+// is part of the representation rather than the version, so If-Match only compares the post itself.
+// variants distinguish alternative representations of the same version, such as rendered HTML.
+func postETag(post *models.PostDetailed, author *models.User, variants ...string) string {
+	version := []string{
+		"post",
+		strconv.FormatInt(post.Post.ID, 10),
+		utils.VersionTag(post.Post.UpdatedAt),
+	}
+	if author != nil {
+		variants = append([]string{strconv.FormatInt(author.ID, 10), utils.VersionTag(author.UpdatedAt)}, variants...)
+	}
+	return utils.RepresentationETag(version, variants...)
+}
+
+func postLastModified(post *models.PostDetailed, author *models.User) time.Time {
+	if author != nil && author.UpdatedAt.After(post.Post.UpdatedAt) {
+		return author.UpdatedAt
+	}
+	return post.Post.UpdatedAt
+}
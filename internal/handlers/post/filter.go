@@ -0,0 +1,129 @@
+package post_handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"pinstack-api-gateway/internal/models"
+	"strings"
+)
+
+const (
+	maxFilterTags = 10
+	tagScanBatch  = 100
+	tagScanLimit  = 1000
+)
+
+// parseTagNames reads tags from comma separated and repeated "tags" parameters, lower-cased and deduplicated.
+func parseTagNames(values []string) []string {
+	seen := make(map[string]struct{})
+	tags := make([]string, 0)
+	for _, value := range values {
+		for _, tag := range strings.Split(value, ",") {
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			if tag == "" {
+				continue
+			}
+			if _, ok := seen[tag]; ok {
+				continue
+			}
+			seen[tag] = struct{}{}
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// ErrFilterScanLimit is returned when a filter evaluated by the gateway would have to scan more
+// posts than tagScanLimit, so neither the page nor the total could be computed correctly.
+var ErrFilterScanLimit = errors.New("filter matches too many posts, narrow it with author_id or a date range")
+
+// filteredInGateway reports whether filters need the gateway-side filter stage. The post service
+// matches tag_names with "any" semantics, so "all" is evaluated here.
+func filteredInGateway(filters *models.PostFilters) bool {
+	return filters.TagMode == models.TagMatchAll && len(filters.TagNames) >= 2
+}
+
+// listPosts behaves like PostClient.ListPosts, but applies the gateway-side filter stage
+// for filters the post service cannot express.
+func (h *PostHandler) listPosts(ctx context.Context, filters *models.PostFilters) ([]*models.PostDetailed, int64, error) {
+	if !filteredInGateway(filters) {
+		return h.postClient.ListPosts(ctx, filters)
+	}
+
+	matched, err := h.scanPosts(ctx, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+	total := int64(len(matched))
+	start := 0
+	if filters.Offset != nil {
+		start = min(max(*filters.Offset, 0), len(matched))
+	}
+	end := len(matched)
+	if filters.Limit != nil && *filters.Limit > 0 {
+		end = min(start+*filters.Limit, len(matched))
+	}
+	return matched[start:end], total, nil
+}
+
+// scanPosts pages through every candidate the post service returns for filters and keeps the
+// matches, newest first. It fails with ErrFilterScanLimit rather than return a truncated result.
+func (h *PostHandler) scanPosts(ctx context.Context, filters *models.PostFilters) ([]*models.PostDetailed, error) {
+	scan := *filters
+	matched := make([]*models.PostDetailed, 0)
+	for offset := 0; ; offset += tagScanBatch {
+		if offset >= tagScanLimit {
+			h.log.Debug("Filter scan limit reached",
+				slog.Any("tags", filters.TagNames),
+				slog.Int("scan_limit", tagScanLimit),
+			)
+			return nil, ErrFilterScanLimit
+		}
+		batchOffset, batchLimit := offset, tagScanBatch
+		scan.Offset, scan.Limit = &batchOffset, &batchLimit
+
+		batch, total, err := h.postClient.ListPosts(ctx, &scan)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range batch {
+			if matchesFilters(p, filters) {
+				matched = append(matched, p)
+			}
+		}
+		if len(batch) < tagScanBatch || int64(offset+len(batch)) >= total {
+			break
+		}
+	}
+	sortPostsNewestFirst(matched)
+	return matched, nil
+}
+
+// matchesFilters checks a post against the tag and date filters.
+func matchesFilters(p *models.PostDetailed, filters *models.PostFilters) bool {
+	if filters.CreatedAfter != nil && !p.Post.CreatedAt.After(*filters.CreatedAfter) {
+		return false
+	}
+	if filters.CreatedBefore != nil && !p.Post.CreatedAt.Before(*filters.CreatedBefore) {
+		return false
+	}
+	if len(filters.TagNames) == 0 {
+		return true
+	}
+
+	postTags := make(map[string]struct{}, len(p.Tags))
+	for _, t := range p.Tags {
+		postTags[strings.ToLower(t.Name)] = struct{}{}
+	}
+	found := 0
+	for _, tag := range filters.TagNames {
+		if _, ok := postTags[tag]; ok {
+			found++
+		}
+	}
+	if filters.TagMode == models.TagMatchAll {
+		return found == len(filters.TagNames)
+	}
+	return found > 0
+}
@@ -5,6 +5,8 @@ import (
 	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
 	"log/slog"
 	"net/http"
+	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/userview"
 	"pinstack-api-gateway/internal/utils"
 	"strconv"
 
@@ -16,28 +18,23 @@ type GetPostRequest struct {
 }
 
 type GetPostResponse struct {
-	ID        int64           `json:"id"`
-	Author    *GetPostUser    `json:"author,omitempty"`
-	Title     string          `json:"title"`
-	Content   *string         `json:"content,omitempty"`
-	CreatedAt string          `json:"created_at"`
-	UpdatedAt string          `json:"updated_at"`
-	Media     []*GetPostMedia `json:"media,omitempty"`
-	Tags      []*GetPostTag   `json:"tags,omitempty"`
-}
-
-type GetPostUser struct {
-	ID        int64   `json:"id"`
-	Username  string  `json:"username"`
-	FullName  *string `json:"full_name,omitempty"`
-	AvatarURL *string `json:"avatar_url,omitempty"`
+	ID          int64            `json:"id"`
+	Author      *userview.Author `json:"author,omitempty"`
+	Title       string           `json:"title"`
+	Content     *string          `json:"content,omitempty"`
+	ContentHTML *string          `json:"content_html,omitempty"`
+	CreatedAt   string           `json:"created_at"`
+	UpdatedAt   string           `json:"updated_at"`
+	Media       []*GetPostMedia  `json:"media,omitempty"`
+	Tags        []*GetPostTag    `json:"tags,omitempty"`
 }
 
 type GetPostMedia struct {
-	ID       int64  `json:"id"`
-	URL      string `json:"url"`
-	Type     string `json:"type"`
-	Position int32  `json:"position"`
+	ID       int64                 `json:"id"`
+	URL      string                `json:"url"`
+	Type     string                `json:"type"`
+	Position int32                 `json:"position"`
+	Variants *models.MediaVariants `json:"variants,omitempty"`
 }
 
 type GetPostTag struct {
@@ -48,12 +45,23 @@ type GetPostTag struct {
 // Get godoc
 // @Summary Get post by ID
 // @Description Get detailed information about a specific post
+// @Description With Accept: application/x-protobuf the post is the reduced post.v1.Post message: the author is only author_id,
+// @Description and content_html and media variants are left out. It cannot be combined with fields (406).
 // @Tags posts
 // @Produce json
+// @Produce application/msgpack
+// @Produce application/x-protobuf
 // @Param id path string true "Post ID"
+// @Param render query string false "Set to html to include content_html rendered from Markdown" Enums(html)
+// @Param fields query string false "Comma separated fields to return, e.g. title,author.username"
+// @Param expand query string false "Comma separated expansions (author, media, tags); all by default, none when empty"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Param If-Modified-Since header string false "Last-Modified from a previous response"
 // @Success 200 {object} GetPostResponse "Post information"
+// @Success 304 "Not modified"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 404 {object} map[string]string "Post not found"
+// @Failure 406 {object} map[string]string "Requested media type cannot represent the response"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /posts/{id} [get]
 func (h *PostHandler) Get(w http.ResponseWriter, r *http.Request) {
@@ -70,6 +78,19 @@ func (h *PostHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	renderHTML, ok := renderHTMLRequested(r)
+	if !ok {
+		h.log.Debug("Invalid render parameter", slog.String("render", r.URL.Query().Get("render")))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+		return
+	}
+	sel, err := parsePostSelection(r.URL.Query())
+	if err != nil {
+		h.log.Debug("Invalid fields or expand parameter", slog.String("query", r.URL.RawQuery))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+		return
+	}
+
 	post, err := h.postClient.GetPostByID(r.Context(), id)
 	if err != nil {
 		switch {
@@ -97,39 +118,60 @@ func (h *PostHandler) Get(w http.ResponseWriter, r *http.Request) {
 		UpdatedAt: post.Post.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 
-	author, err := h.userClient.GetUser(r.Context(), post.Post.AuthorID)
-	if err != nil {
-		switch {
-		case errors.Is(err, custom_errors.ErrUserNotFound):
-			h.log.Warn("Author not found for post", slog.Int64("author_id", post.Post.AuthorID))
-			author = utils.GenerateUnknownAuthor()
-		default:
-			h.log.Error("Failed to get user", slog.Int64("id", post.Post.AuthorID), slog.String("error", err.Error()))
-			utils.SendError(w, http.StatusInternalServerError, custom_errors.ErrExternalServiceError.Error())
-			return
+	var author *models.User
+	if sel.Expands(expandAuthor) {
+		author, err = h.userClient.GetUser(r.Context(), post.Post.AuthorID)
+		if err != nil {
+			switch {
+			case errors.Is(err, custom_errors.ErrUserNotFound):
+				h.log.Warn("Author not found for post", slog.Int64("author_id", post.Post.AuthorID))
+				author = utils.GenerateUnknownAuthor()
+			default:
+				h.log.Error("Failed to get user", slog.Int64("id", post.Post.AuthorID), slog.String("error", err.Error()))
+				utils.SendError(w, http.StatusInternalServerError, custom_errors.ErrExternalServiceError.Error())
+				return
+			}
+		}
+	}
+
+	variants := []string{utils.CodecVariant(w), sel.Variant()}
+	if renderHTML {
+		variants = append(variants, "html")
+	}
+	lastModified := postLastModified(post, author)
+	if h.mediaURLs.Signed() {
+		// Embedded media URLs are re-signed every window, so a cached copy is only fresh within one.
+		window := h.mediaURLs.Window()
+		variants = append(variants, "media", utils.VersionTag(window))
+		if window.After(lastModified) {
+			lastModified = window
 		}
 	}
+	if utils.CheckNotModified(w, r, postETag(post, author, variants...), lastModified) {
+		return
+	}
+	if renderHTML {
+		resp.ContentHTML = h.contentHTML(post.Post)
+	}
 
-	resp.Author = &GetPostUser{
-		ID:        author.ID,
-		Username:  author.Username,
-		FullName:  author.FullName,
-		AvatarURL: author.AvatarURL,
+	if author != nil {
+		resp.Author = userview.NewAuthor(author, h.avatarURL(author))
 	}
 
-	if post.Media != nil {
+	if post.Media != nil && sel.Expands(expandMedia) {
 		media := make([]*GetPostMedia, 0, len(post.Media))
 		for _, m := range post.Media {
 			media = append(media, &GetPostMedia{
 				ID:       m.ID,
-				URL:      m.URL,
+				URL:      h.mediaURLs.Public(m.URL),
 				Type:     string(m.Type),
 				Position: m.Position,
+				Variants: h.mediaVariants(m),
 			})
 		}
 		resp.Media = media
 	}
-	if post.Tags != nil {
+	if post.Tags != nil && sel.Expands(expandTags) {
 		tags := make([]*GetPostTag, 0, len(post.Tags))
 		for _, t := range post.Tags {
 			tags = append(tags, &GetPostTag{
@@ -139,5 +181,6 @@ func (h *PostHandler) Get(w http.ResponseWriter, r *http.Request) {
 		}
 		resp.Tags = tags
 	}
-	utils.Send(w, http.StatusOK, resp)
+
+	utils.SendSelected(w, http.StatusOK, sel, resp)
 }
@@ -1,21 +1,53 @@
 package post_handler
 
 import (
+	"pinstack-api-gateway/internal/avatar"
 	post_client "pinstack-api-gateway/internal/clients/post"
 	user_client "pinstack-api-gateway/internal/clients/user"
+	"pinstack-api-gateway/internal/content"
 	"pinstack-api-gateway/internal/logger"
+	"pinstack-api-gateway/internal/media"
+	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/pagination"
 )
 
 type PostHandler struct {
-	postClient post_client.PostClient
-	userClient user_client.UserClient
-	log        *logger.Logger
+	postClient      post_client.PostClient
+	userClient      user_client.UserClient
+	cursors         *pagination.CursorCodec
+	mentionNotifier MentionNotifier
+	mentions        *mentionCache
+	markdown        *content.MarkdownRenderer
+	mediaURLs       *media.URLResolver
+	avatars         *avatar.Links
+	moderator       ContentModerator
+	log             *logger.Logger
 }
 
-func NewPostHandler(postClient post_client.PostClient, userClient user_client.UserClient, log *logger.Logger) *PostHandler {
+func NewPostHandler(postClient post_client.PostClient, userClient user_client.UserClient, cursors *pagination.CursorCodec, mentionNotifier MentionNotifier, mediaURLs *media.URLResolver, avatars *avatar.Links, moderator ContentModerator, log *logger.Logger) *PostHandler {
 	return &PostHandler{
-		postClient: postClient,
-		userClient: userClient,
-		log:        log,
+		postClient:      postClient,
+		userClient:      userClient,
+		cursors:         cursors,
+		mentionNotifier: mentionNotifier,
+		mentions:        newMentionCache(),
+		markdown:        content.NewMarkdownRenderer(markdownCacheSize),
+		mediaURLs:       mediaURLs,
+		avatars:         avatars,
+		moderator:       moderator,
+		log:             log,
 	}
 }
+
+// mediaVariants returns the sized variants of an image attached to a post, if it was uploaded through the gateway.
+func (h *PostHandler) mediaVariants(m *models.PostMedia) *models.MediaVariants {
+	if m.Type != models.MediaTypeImage {
+		return nil
+	}
+	return h.mediaURLs.Variants(m.URL)
+}
+
+// avatarURL returns the avatar to show for a post author: their own, or the identicon served by the avatar endpoint.
+func (h *PostHandler) avatarURL(author *models.User) *string {
+	return h.mediaURLs.PublicPtr(h.avatars.Or(author.AvatarURL, author.ID, author.Username))
+}
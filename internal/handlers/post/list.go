@@ -5,9 +5,14 @@ import (
 	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/pagination"
+	"pinstack-api-gateway/internal/selection"
+	"pinstack-api-gateway/internal/userview"
 	"pinstack-api-gateway/internal/utils"
 	"strconv"
+	"strings"
 	"time"
 
 	"google.golang.org/grpc/codes"
@@ -16,40 +21,52 @@ import (
 
 type ListPostsResponse struct {
 	Posts []ListPostItem `json:"posts"`
-	Total int64          `json:"total"`
+	pagination.Meta
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
 
 type ListPostItem struct {
-	ID        int64               `json:"id"`
-	Title     string              `json:"title"`
-	Content   *string             `json:"content,omitempty"`
-	CreatedAt string              `json:"created_at"`
-	UpdatedAt string              `json:"updated_at"`
-	Author    *ListPostAuthor     `json:"author,omitempty"`
-	Media     []PostMediaResponse `json:"media,omitempty"`
-	Tags      []TagResponse       `json:"tags,omitempty"`
-}
-
-type ListPostAuthor struct {
-	ID        int64   `json:"id"`
-	Username  string  `json:"username"`
-	FullName  *string `json:"full_name,omitempty"`
-	AvatarURL *string `json:"avatar_url,omitempty"`
+	ID          int64               `json:"id"`
+	Title       string              `json:"title"`
+	Content     *string             `json:"content,omitempty"`
+	ContentHTML *string             `json:"content_html,omitempty"`
+	CreatedAt   string              `json:"created_at"`
+	UpdatedAt   string              `json:"updated_at"`
+	Author      *userview.Author    `json:"author,omitempty"`
+	Media       []PostMediaResponse `json:"media,omitempty"`
+	Tags        []TagResponse       `json:"tags,omitempty"`
 }
 
 // List godoc
 // @Summary List posts with filters
-// @Description Get a list of posts with optional filtering by author and date range
+// @Description Get a list of posts with optional filtering by author, tags and date range.
+// @Description Supports offset pagination and opaque cursor pagination; next/prev pages are also advertised in the Link header.
+// @Description With Accept: application/x-protobuf the page is the reduced post.v1.ListPostsResponse: posts and total only,
+// @Description with authors as author_id and no cursors or other pagination meta, so paginate with page or offset.
+// @Description It cannot be combined with fields (406).
 // @Tags posts
 // @Accept json
 // @Produce json
+// @Produce application/msgpack
+// @Produce application/x-protobuf
 // @Param author_id query int false "Filter by author ID"
 // @Param created_after query string false "Filter posts created after this time (RFC3339 format)"
 // @Param created_before query string false "Filter posts created before this time (RFC3339 format)"
-// @Param offset query int false "Pagination offset"
-// @Param limit query int false "Pagination limit"
+// @Param tags query string false "Comma separated tag names (max 10)"
+// @Param tag_mode query string false "Whether posts must have any or all of the tags" Enums(any, all) default(any)
+// @Param page query int false "Page number, starting at 1" default(1)
+// @Param offset query int false "Pagination offset; cannot be combined with page"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param render query string false "Set to html to include content_html rendered from Markdown" Enums(html)
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor; cannot be combined with page or offset"
+// @Param fields query string false "Comma separated fields to return for each post, e.g. title,author.username"
+// @Param expand query string false "Comma separated expansions (author, media, tags); all by default, none when empty"
 // @Success 200 {object} ListPostsResponse "List of posts"
+// @Header 200 {string} Link "RFC 8288 pagination links"
 // @Failure 400 {object} map[string]string "Bad request"
+// @Failure 406 {object} map[string]string "Requested media type cannot represent the response"
+// @Failure 422 {object} map[string]string "tag_mode=all matches too many posts to evaluate; narrow the filter"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /posts/list [get]
 func (h *PostHandler) List(w http.ResponseWriter, r *http.Request) {
@@ -88,26 +105,46 @@ func (h *PostHandler) List(w http.ResponseWriter, r *http.Request) {
 		createdBeforeTime = &t
 	}
 
-	var offset *int
-	if offsetStr := query.Get("offset"); offsetStr != "" {
-		offsetVal, err := strconv.Atoi(offsetStr)
-		if err != nil {
-			h.log.Debug("Invalid offset format", slog.String("error", err.Error()))
-			utils.SendError(w, http.StatusBadRequest, custom_errors.ErrInvalidInput.Error())
+	tagNames := parseTagNames(query["tags"])
+	if len(tagNames) > maxFilterTags {
+		h.log.Debug("Too many tags in filter", slog.Int("count", len(tagNames)))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+		return
+	}
+	tagMode := models.TagMatchAny
+	if modeStr := query.Get("tag_mode"); modeStr != "" {
+		tagMode = models.TagMatchMode(strings.ToLower(modeStr))
+		if tagMode != models.TagMatchAny && tagMode != models.TagMatchAll {
+			h.log.Debug("Invalid tag_mode", slog.String("tag_mode", modeStr))
+			utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
 			return
 		}
-		offset = &offsetVal
+	}
+	if createdAfterTime != nil && createdBeforeTime != nil && !createdAfterTime.Before(*createdBeforeTime) {
+		h.log.Debug("Empty date range", slog.Time("created_after", *createdAfterTime), slog.Time("created_before", *createdBeforeTime))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+		return
 	}
 
-	var limit *int
-	if limitStr := query.Get("limit"); limitStr != "" {
-		limitVal, err := strconv.Atoi(limitStr)
-		if err != nil {
-			h.log.Debug("Invalid limit format", slog.String("error", err.Error()))
-			utils.SendError(w, http.StatusBadRequest, custom_errors.ErrInvalidInput.Error())
-			return
-		}
-		limit = &limitVal
+	renderHTML, ok := renderHTMLRequested(r)
+	if !ok {
+		h.log.Debug("Invalid render parameter", slog.String("render", r.URL.Query().Get("render")))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+		return
+	}
+
+	sel, err := parsePostSelection(query)
+	if err != nil {
+		h.log.Debug("Invalid fields or expand parameter", slog.String("query", r.URL.RawQuery))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+		return
+	}
+
+	params, err := pagination.Parse(query)
+	if err != nil {
+		h.log.Debug("Invalid pagination parameters", slog.String("error", err.Error()))
+		utils.SendError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
 	filters := models.PostFilters{}
@@ -120,14 +157,55 @@ func (h *PostHandler) List(w http.ResponseWriter, r *http.Request) {
 	if createdBeforeTime != nil {
 		filters.CreatedBefore = createdBeforeTime
 	}
-	if offset != nil {
-		filters.Offset = offset
+	if len(tagNames) > 0 {
+		filters.TagNames = tagNames
+		filters.TagMode = tagMode
 	}
-	if limit != nil {
-		filters.Limit = limit
+	filters.Offset = &params.Offset
+	filters.Limit = &params.Limit
+
+	cursorStr := query.Get("cursor")
+	if cursorStr != "" && (query.Get("offset") != "" || query.Get("page") != "") {
+		h.log.Debug("cursor is mutually exclusive with page and offset")
+		utils.SendError(w, http.StatusBadRequest, pagination.ErrInvalidPagination.Error())
+		return
 	}
 
-	posts, total, err := h.postClient.ListPosts(r.Context(), &filters)
+	var (
+		posts            []*models.PostDetailed
+		total            int64
+		hasNext, hasPrev bool
+	)
+	if cursorStr != "" {
+		cursor, decodeErr := h.cursors.Decode(cursorStr)
+		if decodeErr != nil {
+			h.log.Debug("Invalid cursor", slog.String("error", decodeErr.Error()))
+			utils.SendError(w, http.StatusBadRequest, pagination.ErrInvalidCursor.Error())
+			return
+		}
+		if hasFilterParams(query) && !pagination.SameFilters(cursor.Filters, cursorFilters(&filters)) {
+			h.log.Debug("Cursor filters do not match request filters")
+			utils.SendError(w, http.StatusBadRequest, pagination.ErrInvalidCursor.Error())
+			return
+		}
+		filters, err = postFiltersFromCursor(cursor.Filters)
+		if err != nil {
+			utils.SendError(w, http.StatusBadRequest, pagination.ErrInvalidCursor.Error())
+			return
+		}
+
+		posts, total, hasNext, hasPrev, err = h.listPostsByCursor(r.Context(), filters, cursor, params.Limit)
+	} else {
+		posts, total, err = h.listPosts(r.Context(), &filters)
+		if err == nil {
+			hasPrev = params.Offset > 0
+			hasNext = int64(params.Offset+len(posts)) < total
+		}
+	}
+	if errors.Is(err, ErrFilterScanLimit) {
+		utils.SendError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
 	if err != nil {
 		h.log.Error("list posts failed", slog.String("error", err.Error()))
 		if st, ok := status.FromError(err); ok {
@@ -144,61 +222,108 @@ func (h *PostHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	nextCursor, prevCursor, err := h.pageCursors(posts, cursorFilters(&filters), hasNext, hasPrev)
+	if err != nil {
+		h.log.Error("Failed to encode cursors", slog.String("error", err.Error()))
+		utils.SendError(w, http.StatusInternalServerError, custom_errors.ErrExternalServiceError.Error())
+		return
+	}
+
 	resp := ListPostsResponse{
-		Posts: make([]ListPostItem, len(posts)),
-		Total: total,
+		Posts:      make([]ListPostItem, len(posts)),
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+	}
+	if cursorStr != "" {
+		// Keyset pages have no stable page number or offset.
+		resp.Meta = pagination.Meta{Total: total, Limit: params.Limit, HasMore: hasNext}
+	} else {
+		resp.Meta = params.Meta(total, len(posts))
 	}
 	for i, p := range posts {
-		item := ListPostItem{
-			ID:        p.Post.ID,
-			Title:     p.Post.Title,
-			Content:   p.Post.Content,
-			CreatedAt: p.Post.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt: p.Post.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		var author *models.User
+		if sel.Expands(expandAuthor) {
+			author, err = h.userClient.GetUser(r.Context(), p.Post.AuthorID)
+			if err != nil {
+				switch {
+				case errors.Is(err, custom_errors.ErrUserNotFound):
+					h.log.Warn("author not found, using placeholder", slog.Int64("authorID", p.Post.AuthorID))
+					author = utils.GenerateUnknownAuthor()
+				default:
+					h.log.Error("Failed to get user", slog.Int64("id", p.Post.AuthorID), slog.String("error", err.Error()))
+					utils.SendError(w, http.StatusInternalServerError, custom_errors.ErrExternalServiceError.Error())
+					return
+				}
+			}
 		}
+		resp.Posts[i] = h.listItem(p, author, renderHTML, sel)
+	}
 
-		author, err := h.userClient.GetUser(r.Context(), p.Post.AuthorID)
-		if err != nil {
-			switch {
-			case errors.Is(err, custom_errors.ErrUserNotFound):
-				h.log.Warn("author not found, using placeholder", slog.Int64("authorID", p.Post.AuthorID))
-				author = utils.GenerateUnknownAuthor()
-			default:
-				h.log.Error("Failed to get user", slog.Int64("id", p.Post.AuthorID), slog.String("error", err.Error()))
-				utils.SendError(w, http.StatusInternalServerError, custom_errors.ErrExternalServiceError.Error())
-				return
-			}
+	if cursorStr != "" {
+		links := make(map[string]map[string]string, 2)
+		if nextCursor != "" {
+			links["next"] = map[string]string{"cursor": nextCursor}
+		}
+		if prevCursor != "" {
+			links["prev"] = map[string]string{"cursor": prevCursor}
 		}
+		if link := pagination.LinkHeader(r, links, "next", "prev"); link != "" {
+			w.Header().Set("Link", link)
+		}
+	} else {
+		params.SetLinkHeader(w, r, resp.Meta)
+	}
 
-		item.Author = &ListPostAuthor{
-			ID:        author.ID,
-			Username:  author.Username,
-			FullName:  author.FullName,
-			AvatarURL: author.AvatarURL,
+	utils.SendSelected(w, http.StatusOK, sel, resp, "posts")
+}
+
+func hasFilterParams(query url.Values) bool {
+	for _, key := range []string{"author_id", "created_after", "created_before", "tags", "tag_mode"} {
+		if query.Get(key) != "" {
+			return true
 		}
+	}
+	return false
+}
 
-		if len(p.Media) > 0 {
-			item.Media = make([]PostMediaResponse, len(p.Media))
-			for j, m := range p.Media {
-				item.Media[j] = PostMediaResponse{
-					ID:       m.ID,
-					URL:      m.URL,
-					Type:     string(m.Type),
-					Position: m.Position,
-				}
+// listItem builds the summary of a post used by the list and batch endpoints.
+// author is nil when it was not expanded.
+func (h *PostHandler) listItem(p *models.PostDetailed, author *models.User, renderHTML bool, sel *selection.Selection) ListPostItem {
+	item := ListPostItem{
+		ID:        p.Post.ID,
+		Title:     p.Post.Title,
+		Content:   p.Post.Content,
+		CreatedAt: p.Post.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt: p.Post.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if author != nil {
+		item.Author = userview.NewAuthor(author, h.avatarURL(author))
+	}
+	if renderHTML {
+		item.ContentHTML = h.contentHTML(p.Post)
+	}
+
+	if len(p.Media) > 0 && sel.Expands(expandMedia) {
+		item.Media = make([]PostMediaResponse, len(p.Media))
+		for j, m := range p.Media {
+			item.Media[j] = PostMediaResponse{
+				ID:       m.ID,
+				URL:      h.mediaURLs.Public(m.URL),
+				Type:     string(m.Type),
+				Position: m.Position,
+				Variants: h.mediaVariants(m),
 			}
 		}
+	}
 
-		if len(p.Tags) > 0 {
-			item.Tags = make([]TagResponse, len(p.Tags))
-			for k, t := range p.Tags {
-				item.Tags[k] = TagResponse{
-					ID:   t.ID,
-					Name: t.Name,
-				}
+	if len(p.Tags) > 0 && sel.Expands(expandTags) {
+		item.Tags = make([]TagResponse, len(p.Tags))
+		for k, t := range p.Tags {
+			item.Tags[k] = TagResponse{
+				ID:   t.ID,
+				Name: t.Name,
 			}
 		}
-		resp.Posts[i] = item
 	}
-	utils.Send(w, http.StatusOK, resp)
+	return item
 }
@@ -0,0 +1,155 @@
+package post_handler
+
+import (
+	"context"
+	"errors"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"log/slog"
+	"pinstack-api-gateway/internal/content"
+	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/utils"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	maxMentionsPerPost    = 10
+	mentionLookupWorkers  = 5
+	mentionCacheTTL       = 5 * time.Minute
+	mentionCacheMaxLength = 10000
+)
+
+// MentionNotifier is told about users newly mentioned in a post. Implementations must not block the request.
+type MentionNotifier interface {
+	NotifyMention(postID, authorID, mentionedUserID int64)
+}
+
+type MentionResponse struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+// mentionCache remembers resolved usernames so popular mentions do not hit the user service every time.
+// Misses are not cached, so a user who registers right after being mentioned is found by the next post.
+type mentionCache struct {
+	mu      sync.Mutex
+	entries map[string]mentionCacheEntry
+}
+
+type mentionCacheEntry struct {
+	user      *models.User
+	expiresAt time.Time
+}
+
+func newMentionCache() *mentionCache {
+	return &mentionCache{entries: make(map[string]mentionCacheEntry)}
+}
+
+func (c *mentionCache) get(username string) (*models.User, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[strings.ToLower(username)]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.user, true
+}
+
+func (c *mentionCache) put(username string, user *models.User) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if len(c.entries) >= mentionCacheMaxLength {
+		for k, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= mentionCacheMaxLength {
+			c.entries = make(map[string]mentionCacheEntry)
+		}
+	}
+	c.entries[strings.ToLower(username)] = mentionCacheEntry{user: user, expiresAt: now.Add(mentionCacheTTL)}
+}
+
+// resolveMentions looks up the users mentioned in text, at most maxMentionsPerPost of them.
+// Unknown usernames are dropped; lookup failures are logged and skipped so they never fail the post.
+func (h *PostHandler) resolveMentions(ctx context.Context, text *string) []*models.User {
+	if text == nil {
+		return nil
+	}
+	usernames := content.ExtractMentions(*text)
+	if len(usernames) > maxMentionsPerPost {
+		h.log.Debug("Too many mentions, ignoring the rest", slog.Int("count", len(usernames)), slog.Int("limit", maxMentionsPerPost))
+		usernames = usernames[:maxMentionsPerPost]
+	}
+
+	users := make([]*models.User, len(usernames))
+	pending := make([]int, 0, len(usernames))
+	for i, username := range usernames {
+		if user, ok := h.mentions.get(username); ok {
+			users[i] = user
+			continue
+		}
+		pending = append(pending, i)
+	}
+	utils.FanOut(ctx, len(pending), mentionLookupWorkers, func(ctx context.Context, j int) error {
+		i := pending[j]
+		username := usernames[i]
+		user, err := h.userClient.GetUserByUsername(ctx, username)
+		switch {
+		case err == nil:
+			h.mentions.put(username, user)
+			users[i] = user
+		case errors.Is(err, custom_errors.ErrUserNotFound):
+			h.log.Debug("Mentioned user not found", slog.String("username", username))
+		default:
+			h.log.Warn("Failed to resolve mention", slog.String("username", username), slog.String("error", err.Error()))
+		}
+		return nil
+	})
+
+	resolved := make([]*models.User, 0, len(users))
+	seen := make(map[int64]struct{}, len(users))
+	for _, u := range users {
+		if u == nil {
+			continue
+		}
+		if _, ok := seen[u.ID]; ok {
+			continue
+		}
+		seen[u.ID] = struct{}{}
+		resolved = append(resolved, u)
+	}
+	return resolved
+}
+
+// notifyMentions notifies mentioned users except the author and anyone in previously.
+func (h *PostHandler) notifyMentions(postID, authorID int64, mentioned, previously []*models.User) {
+	if h.mentionNotifier == nil {
+		return
+	}
+	skip := make(map[int64]struct{}, len(previously)+1)
+	skip[authorID] = struct{}{}
+	for _, u := range previously {
+		skip[u.ID] = struct{}{}
+	}
+	for _, u := range mentioned {
+		if _, ok := skip[u.ID]; ok {
+			continue
+		}
+		h.mentionNotifier.NotifyMention(postID, authorID, u.ID)
+	}
+}
+
+func mentionResponses(users []*models.User) []MentionResponse {
+	if len(users) == 0 {
+		return nil
+	}
+	resp := make([]MentionResponse, len(users))
+	for i, u := range users {
+		resp[i] = MentionResponse{ID: u.ID, Username: u.Username}
+	}
+	return resp
+}
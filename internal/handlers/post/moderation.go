@@ -0,0 +1,55 @@
+package post_handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"pinstack-api-gateway/internal/moderation"
+	"pinstack-api-gateway/internal/utils"
+)
+
+// ContentModerator decides whether post content may be stored. A nil moderator lets everything through.
+type ContentModerator interface {
+	Moderate(ctx context.Context, c *moderation.Content) moderation.Decision
+}
+
+type ModerationRejectedResponse struct {
+	Error   string              `json:"error"`
+	Reasons []moderation.Reason `json:"reasons"`
+}
+
+// moderate runs the moderator over c and writes a 422 response if the content is rejected.
+// It reports whether the request may continue.
+func (h *PostHandler) moderate(w http.ResponseWriter, r *http.Request, c *moderation.Content) bool {
+	if h.moderator == nil {
+		return true
+	}
+	decision := h.moderator.Moderate(r.Context(), c)
+	switch decision.Action {
+	case moderation.Reject:
+		h.log.Debug("Post rejected by moderation", slog.Int64("user_id", c.UserID), slog.Any("reasons", decision.Reasons))
+		utils.Send(w, http.StatusUnprocessableEntity, ModerationRejectedResponse{
+			Error:   "content rejected by moderation",
+			Reasons: decision.Reasons,
+		})
+		return false
+	case moderation.Flag:
+		h.log.Info("Post flagged by moderation", slog.Int64("user_id", c.UserID), slog.Int64("post_id", c.PostID), slog.Any("reasons", decision.Reasons))
+	}
+	return true
+}
+
+func mediaItemURLs(items []*MediaItemInput) []string {
+	urls := make([]string, len(items))
+	for i, item := range items {
+		urls[i] = item.URL
+	}
+	return urls
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,60 @@
+package post_handler
+
+import (
+	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
+	"pinstack-api-gateway/internal/utils"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ToProto returns the post as post.v1.Post, a reduced representation: the author is kept only as
+// author_id and content_html and media variants have no field in the message.
+func (r *GetPostResponse) ToProto() proto.Message {
+	msg := &pb.Post{
+		Id:        r.ID,
+		Title:     r.Title,
+		CreatedAt: utils.ProtoTimestamp(r.CreatedAt),
+		UpdatedAt: utils.ProtoTimestamp(r.UpdatedAt),
+	}
+	if r.Author != nil {
+		msg.AuthorId = r.Author.ID
+	}
+	if r.Content != nil {
+		msg.Content = *r.Content
+	}
+	for _, m := range r.Media {
+		msg.Media = append(msg.Media, &pb.Media{Id: m.ID, Url: m.URL, Type: m.Type, Position: m.Position})
+	}
+	for _, t := range r.Tags {
+		msg.Tags = append(msg.Tags, t.Name)
+	}
+	return msg
+}
+
+// ToProto returns the page as post.v1.ListPostsResponse, which carries posts and total but no
+// cursors or other pagination meta.
+func (r ListPostsResponse) ToProto() proto.Message {
+	msg := &pb.ListPostsResponse{Posts: make([]*pb.Post, 0, len(r.Posts)), Total: r.Total}
+	for _, item := range r.Posts {
+		post := &pb.Post{
+			Id:        item.ID,
+			Title:     item.Title,
+			CreatedAt: utils.ProtoTimestamp(item.CreatedAt),
+			UpdatedAt: utils.ProtoTimestamp(item.UpdatedAt),
+		}
+		if item.Author != nil {
+			post.AuthorId = item.Author.ID
+		}
+		if item.Content != nil {
+			post.Content = *item.Content
+		}
+		for _, m := range item.Media {
+			post.Media = append(post.Media, &pb.Media{Id: m.ID, Url: m.URL, Type: m.Type, Position: m.Position})
+		}
+		for _, t := range item.Tags {
+			post.Tags = append(post.Tags, t.Name)
+		}
+		msg.Posts = append(msg.Posts, post)
+	}
+	return msg
+}
@@ -0,0 +1,35 @@
+package post_handler
+
+import (
+	"log/slog"
+	"net/http"
+	"pinstack-api-gateway/internal/models"
+)
+
+// markdownCacheSize is how many rendered post versions are kept in memory.
+const markdownCacheSize = 5000
+
+// renderHTMLRequested reads the ?render= toggle. ok is false for values other than "html".
+func renderHTMLRequested(r *http.Request) (render bool, ok bool) {
+	switch r.URL.Query().Get("render") {
+	case "":
+		return false, true
+	case "html":
+		return true, true
+	default:
+		return false, false
+	}
+}
+
+// contentHTML renders post content to sanitised HTML. Rendering failures are logged and leave the field out.
+func (h *PostHandler) contentHTML(post *models.Post) *string {
+	if post.Content == nil {
+		return nil
+	}
+	html, err := h.markdown.Render(post.ID, post.UpdatedAt, *post.Content)
+	if err != nil {
+		h.log.Warn("Failed to render post content", slog.Int64("id", post.ID), slog.String("error", err.Error()))
+		return nil
+	}
+	return &html
+}
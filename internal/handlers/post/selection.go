@@ -0,0 +1,19 @@
+package post_handler
+
+import (
+	"net/url"
+	"pinstack-api-gateway/internal/selection"
+)
+
+// Expansions of post responses. All of them are included unless the request lists the ones it wants.
+const (
+	expandAuthor = "author"
+	expandMedia  = "media"
+	expandTags   = "tags"
+)
+
+var postExpansions = []string{expandAuthor, expandMedia, expandTags}
+
+func parsePostSelection(query url.Values) (*selection.Selection, error) {
+	return selection.Parse(query, postExpansions, postExpansions)
+}
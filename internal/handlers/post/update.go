@@ -6,8 +6,11 @@ import (
 	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
 	"log/slog"
 	"net/http"
+	"pinstack-api-gateway/internal/content"
 	"pinstack-api-gateway/internal/middlewares"
 	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/moderation"
+	"pinstack-api-gateway/internal/userview"
 	"pinstack-api-gateway/internal/utils"
 	"strconv"
 
@@ -28,32 +31,34 @@ type UpdatePostResponse struct {
 	Content   *string             `json:"content,omitempty"`
 	CreatedAt string              `json:"created_at"`
 	UpdatedAt string              `json:"updated_at"`
-	Author    *UpdatePostAuthor   `json:"author,omitempty"`
+	Author    *userview.Author    `json:"author,omitempty"`
 	Media     []PostMediaResponse `json:"media,omitempty"`
 	Tags      []TagResponse       `json:"tags,omitempty"`
-}
-
-type UpdatePostAuthor struct {
-	ID        int64   `json:"id"`
-	Username  string  `json:"username"`
-	FullName  *string `json:"full_name,omitempty"`
-	AvatarURL *string `json:"avatar_url,omitempty"`
+	Mentions  []MentionResponse   `json:"mentions,omitempty"`
 }
 
 // Update godoc
 // @Summary Update a post
 // @Description Update an existing post with new data
+// @Description Users newly @mentioned in the content are notified. Tags from #hashtags are recomputed from the resulting content, so hashtags removed from it are dropped from the tags.
+// @Description Tags are lower-cased and must be at most 50 letters, digits, underscores or + . - # characters, including a letter.
+// @Description Changed fields are checked against the moderation rules before the post is stored.
+// @Description If-Match is checked against the current version before the update is sent. The post service has no
+// @Description conditional update, so a concurrent write landing between the check and the update is not detected.
 // @Tags posts
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Post ID"
+// @Param If-Match header string false "ETag of the version being edited"
 // @Param request body UpdatePostRequest true "Post update data"
 // @Success 200 {object} UpdatePostResponse "Post updated successfully"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 403 {object} map[string]string "Forbidden"
 // @Failure 404 {object} map[string]string "Post not found"
+// @Failure 412 {object} map[string]string "Post was modified concurrently"
+// @Failure 422 {object} ModerationRejectedResponse "Content rejected by moderation"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /posts/{id} [put]
 func (h *PostHandler) Update(w http.ResponseWriter, r *http.Request) {
@@ -108,6 +113,67 @@ func (h *PostHandler) Update(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// The stored post is needed to check If-Match, to recompute its hashtags when the content or tags change
+	// and to tell newly added mentions from existing ones.
+	var current *models.PostDetailed
+	if r.Header.Get("If-Match") != "" || req.Content != nil || req.Tags != nil {
+		current, err = h.postClient.GetPostByID(r.Context(), id)
+		if err != nil {
+			switch {
+			case errors.Is(err, custom_errors.ErrPostNotFound):
+				utils.SendError(w, http.StatusNotFound, custom_errors.ErrPostNotFound.Error())
+			default:
+				h.log.Error("get post before update failed", slog.Int64("id", id), slog.String("error", err.Error()))
+				utils.SendError(w, http.StatusInternalServerError, custom_errors.ErrExternalServiceError.Error())
+			}
+			return
+		}
+	}
+	if req.Content != nil || req.Tags != nil {
+		// Hashtags are recomputed from the resulting content, so ones removed from it are dropped.
+		text := req.Content
+		if text == nil {
+			text = current.Post.Content
+		}
+		explicit := req.Tags
+		if explicit == nil {
+			currentTags := make([]string, len(current.Tags))
+			for i, t := range current.Tags {
+				currentTags[i] = t.Name
+			}
+			explicit = content.WithoutHashtags(currentTags, current.Post.Content)
+		}
+		if modelReq.Tags, err = content.MergeTags(explicit, text); err != nil {
+			if req.Tags != nil {
+				h.log.Debug("Invalid post tags", slog.Any("tags", req.Tags), slog.String("error", err.Error()))
+				utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+				return
+			}
+			// Stored tags predating the tag limit or policy are kept as they are.
+			h.log.Debug("Stored tags exceed the tag limit", slog.Int64("id", id), slog.String("error", err.Error()))
+			modelReq.Tags = explicit
+		}
+	}
+	// current is only loaded for If-Match, content or tags, so the version is only built when If-Match is sent.
+	if r.Header.Get("If-Match") != "" && utils.PreconditionFailed(r, postETag(current, nil)) {
+		h.log.Debug("post version mismatch", slog.Int64("id", id), slog.String("if_match", r.Header.Get("If-Match")))
+		utils.SendError(w, http.StatusPreconditionFailed, "precondition failed")
+		return
+	}
+
+	// Only the fields being changed are moderated.
+	if !h.moderate(w, r, &moderation.Content{
+		Operation: moderation.OperationUpdatePost,
+		UserID:    claims.UserID,
+		PostID:    id,
+		Title:     stringValue(req.Title),
+		Text:      stringValue(req.Content),
+		Tags:      req.Tags,
+		MediaURLs: mediaItemURLs(req.MediaItems),
+	}) {
+		return
+	}
+
 	err = h.postClient.UpdatePost(r.Context(), id, modelReq)
 	if err != nil {
 		h.log.Error("Update post failed", slog.String("error", err.Error()))
@@ -158,21 +224,25 @@ func (h *PostHandler) Update(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	resp.Author = &UpdatePostAuthor{
-		ID:        author.ID,
-		Username:  author.Username,
-		FullName:  author.FullName,
-		AvatarURL: author.AvatarURL,
+	utils.SetValidators(w, postETag(updatedPost, author, utils.CodecVariant(w)), postLastModified(updatedPost, author))
+
+	mentions := h.resolveMentions(r.Context(), updatedPost.Post.Content)
+	if req.Content != nil && h.mentionNotifier != nil {
+		h.notifyMentions(updatedPost.Post.ID, updatedPost.Post.AuthorID, mentions, h.resolveMentions(r.Context(), current.Post.Content))
 	}
+	resp.Mentions = mentionResponses(mentions)
+
+	resp.Author = userview.NewAuthor(author, h.avatarURL(author))
 
 	if len(updatedPost.Media) > 0 {
 		resp.Media = make([]PostMediaResponse, len(updatedPost.Media))
 		for i, m := range updatedPost.Media {
 			resp.Media[i] = PostMediaResponse{
 				ID:       m.ID,
-				URL:      m.URL,
+				URL:      h.mediaURLs.Public(m.URL),
 				Type:     string(m.Type),
 				Position: m.Position,
+				Variants: h.mediaVariants(m),
 			}
 		}
 	}
@@ -0,0 +1,112 @@
+package post_handler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"pinstack-api-gateway/internal/avatar"
+	post_client "pinstack-api-gateway/internal/clients/post"
+	user_client "pinstack-api-gateway/internal/clients/user"
+	"pinstack-api-gateway/internal/logger"
+	"pinstack-api-gateway/internal/media"
+	"pinstack-api-gateway/internal/middlewares"
+	"pinstack-api-gateway/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type fakePostClient struct {
+	post_client.PostClient
+	post    *models.PostDetailed
+	gets    int
+	updated *models.UpdatePostDTO
+}
+
+func (f *fakePostClient) GetPostByID(ctx context.Context, id int64) (*models.PostDetailed, error) {
+	f.gets++
+	return f.post, nil
+}
+
+func (f *fakePostClient) UpdatePost(ctx context.Context, id int64, post *models.UpdatePostDTO) error {
+	f.updated = post
+	if post.Title != nil {
+		f.post.Post.Title = *post.Title
+	}
+	return nil
+}
+
+type fakeUserClient struct {
+	user_client.UserClient
+	user *models.User
+}
+
+func (f *fakeUserClient) GetUser(ctx context.Context, id int64) (*models.User, error) {
+	return f.user, nil
+}
+
+func newTestHandler(t *testing.T, posts *fakePostClient) *PostHandler {
+	t.Helper()
+	store, err := media.NewLocalStore(t.TempDir(), "http://localhost/media")
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	users := &fakeUserClient{user: &models.User{ID: 1, Username: "alice"}}
+	log := &logger.Logger{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	return NewPostHandler(posts, users, nil, nil, media.NewURLResolver(store, nil, ""), avatar.NewLinks("http://localhost/users"), nil, log)
+}
+
+func updateRequest(id, body string, header http.Header) *http.Request {
+	r := httptest.NewRequest(http.MethodPut, "/posts/"+id, strings.NewReader(body))
+	for name, values := range header {
+		r.Header[name] = values
+	}
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	ctx := context.WithValue(r.Context(), chi.RouteCtxKey, rctx)
+	ctx = context.WithValue(ctx, middlewares.ClaimsKey, &middlewares.Claims{UserID: 1})
+	return r.WithContext(ctx)
+}
+
+func TestUpdateTitleOnlyWithoutIfMatch(t *testing.T) {
+	now := time.Now()
+	content := "hello"
+	posts := &fakePostClient{post: &models.PostDetailed{Post: &models.Post{
+		ID: 5, AuthorID: 1, Title: "old", Content: &content, CreatedAt: now, UpdatedAt: now,
+	}}}
+	w := httptest.NewRecorder()
+
+	newTestHandler(t, posts).Update(w, updateRequest("5", `{"title":"new title"}`, nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want 200: %s", w.Code, w.Body)
+	}
+	if posts.updated == nil || posts.updated.Title == nil || *posts.updated.Title != "new title" {
+		t.Errorf("got update %+v, want the new title", posts.updated)
+	}
+	if posts.updated.Tags != nil {
+		t.Errorf("title-only update changed tags to %v", posts.updated.Tags)
+	}
+	if posts.gets != 1 {
+		t.Errorf("got %d post lookups, want only the one after the update", posts.gets)
+	}
+}
+
+func TestUpdateIfMatchMismatch(t *testing.T) {
+	now := time.Now()
+	posts := &fakePostClient{post: &models.PostDetailed{Post: &models.Post{ID: 5, AuthorID: 1, Title: "old", CreatedAt: now, UpdatedAt: now}}}
+	w := httptest.NewRecorder()
+
+	newTestHandler(t, posts).Update(w, updateRequest("5", `{"title":"new title"}`, http.Header{"If-Match": {`"stale"`}}))
+
+	if w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("got status %d, want 412: %s", w.Code, w.Body)
+	}
+	if posts.updated != nil {
+		t.Error("post updated despite a stale If-Match")
+	}
+}
@@ -0,0 +1,213 @@
+package profile_handler
+
+import (
+	"context"
+	"errors"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"log/slog"
+	"net/http"
+	"pinstack-api-gateway/internal/middlewares"
+	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/relationship"
+	"pinstack-api-gateway/internal/userview"
+	"pinstack-api-gateway/internal/utils"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	sectionFollowers    = "followers_count"
+	sectionFollowees    = "followees_count"
+	sectionPosts        = "recent_posts"
+	sectionRelationship = "relationship"
+)
+
+type ProfileResponse struct {
+	User            userview.User        `json:"user"`
+	FollowersCount  *int64               `json:"followers_count,omitempty"`
+	FolloweesCount  *int64               `json:"followees_count,omitempty"`
+	PostsCount      *int64               `json:"posts_count,omitempty"`
+	RecentPosts     []ProfilePost        `json:"recent_posts"`
+	Relationship    *relationship.Status `json:"relationship,omitempty"`
+	Partial         bool                 `json:"partial,omitempty"`
+	MissingSections []string             `json:"missing_sections,omitempty"`
+}
+
+type ProfilePost struct {
+	ID        int64          `json:"id"`
+	Title     string         `json:"title"`
+	Content   *string        `json:"content,omitempty"`
+	CreatedAt string         `json:"created_at"`
+	Media     []ProfileMedia `json:"media,omitempty"`
+	Tags      []ProfileTag   `json:"tags,omitempty"`
+}
+
+type ProfileMedia struct {
+	ID       int64  `json:"id"`
+	URL      string `json:"url"`
+	Type     string `json:"type"`
+	Position int32  `json:"position"`
+}
+
+type ProfileTag struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// GetProfile godoc
+// @Summary Get user profile
+// @Description Get a user together with follower/followee counts, recent posts and, for authenticated callers, the caller's relationship to the user.
+// @Description Sections other than the user are loaded concurrently with their own timeout; sections that fail are listed in missing_sections.
+// @Description Authentication is optional; a bearer token that is sent must be valid.
+// @Tags users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} ProfileResponse "User profile"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 401 {object} map[string]string "Invalid bearer token"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /users/{id}/profile [get]
+func (h *ProfileHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrInvalidInput.Error())
+		return
+	}
+	if id < 1 {
+		h.log.Debug("Wrong target id", slog.Int64("id", id))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+		return
+	}
+
+	var viewerID int64
+	if claims, err := middlewares.GetClaimsFromContext(r.Context()); err == nil {
+		viewerID = claims.UserID
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		user    *models.User
+		userErr error
+		resp    = ProfileResponse{RecentPosts: make([]ProfilePost, 0)}
+	)
+	// Sections are abandoned as soon as the user turns out not to exist.
+	sectionsCtx, cancelSections := context.WithCancel(r.Context())
+	defer cancelSections()
+	section := func(name string, fn func(ctx context.Context) error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(sectionsCtx, h.opts.SectionTimeout)
+			defer cancel()
+			if err := fn(ctx); err != nil && sectionsCtx.Err() == nil {
+				h.log.Warn("Profile section failed", slog.String("section", name), slog.Int64("user_id", id), slog.String("error", err.Error()))
+				mu.Lock()
+				resp.MissingSections = append(resp.MissingSections, name)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ctx, cancel := context.WithTimeout(r.Context(), h.opts.SectionTimeout)
+		defer cancel()
+		user, userErr = h.userClient.GetUser(ctx, id)
+		if errors.Is(userErr, custom_errors.ErrUserNotFound) {
+			cancelSections()
+		}
+	}()
+
+	section(sectionFollowers, func(ctx context.Context) error {
+		_, total, err := h.relationClient.GetFollowers(ctx, id, 1, 1)
+		if err == nil {
+			resp.FollowersCount = &total
+		}
+		return err
+	})
+	section(sectionFollowees, func(ctx context.Context) error {
+		_, total, err := h.relationClient.GetFollowees(ctx, id, 1, 1)
+		if err == nil {
+			resp.FolloweesCount = &total
+		}
+		return err
+	})
+	section(sectionPosts, func(ctx context.Context) error {
+		offset, limit := 0, h.opts.RecentPosts
+		posts, total, err := h.postClient.ListPosts(ctx, &models.PostFilters{AuthorID: &id, Offset: &offset, Limit: &limit})
+		if err != nil {
+			return err
+		}
+		resp.PostsCount = &total
+		resp.RecentPosts = h.profilePosts(posts)
+		return nil
+	})
+	if viewerID != 0 && viewerID != id {
+		section(sectionRelationship, func(ctx context.Context) error {
+			status, err := h.relationships.Status(ctx, viewerID, id)
+			if err == nil {
+				resp.Relationship = &status
+			}
+			return err
+		})
+	}
+	wg.Wait()
+
+	if userErr != nil {
+		switch {
+		case errors.Is(userErr, custom_errors.ErrUserNotFound):
+			utils.SendError(w, http.StatusNotFound, custom_errors.ErrUserNotFound.Error())
+		default:
+			h.log.Error("Failed to get profile user", slog.Int64("user_id", id), slog.String("error", userErr.Error()))
+			utils.SendError(w, http.StatusInternalServerError, custom_errors.ErrExternalServiceError.Error())
+		}
+		return
+	}
+
+	resp.User = userview.NewUser(user, h.views.For(r.Context(), user.ID))
+	resp.User.AvatarURL = h.mediaURLs.PublicPtr(user.AvatarURL)
+	sort.Strings(resp.MissingSections)
+	resp.Partial = len(resp.MissingSections) > 0
+
+	utils.Send(w, http.StatusOK, resp)
+}
+
+func (h *ProfileHandler) profilePosts(posts []*models.PostDetailed) []ProfilePost {
+	items := make([]ProfilePost, len(posts))
+	for i, p := range posts {
+		item := ProfilePost{
+			ID:        p.Post.ID,
+			Title:     p.Post.Title,
+			Content:   p.Post.Content,
+			CreatedAt: p.Post.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+		if len(p.Media) > 0 {
+			item.Media = make([]ProfileMedia, len(p.Media))
+			for j, m := range p.Media {
+				item.Media[j] = ProfileMedia{
+					ID:       m.ID,
+					URL:      h.mediaURLs.Public(m.URL),
+					Type:     string(m.Type),
+					Position: m.Position,
+				}
+			}
+		}
+		if len(p.Tags) > 0 {
+			item.Tags = make([]ProfileTag, len(p.Tags))
+			for k, t := range p.Tags {
+				item.Tags[k] = ProfileTag{
+					ID:   t.ID,
+					Name: t.Name,
+				}
+			}
+		}
+		items[i] = item
+	}
+	return items
+}
@@ -0,0 +1,48 @@
+package profile_handler
+
+import (
+	post_client "pinstack-api-gateway/internal/clients/post"
+	relation_client "pinstack-api-gateway/internal/clients/relation"
+	user_client "pinstack-api-gateway/internal/clients/user"
+	"pinstack-api-gateway/internal/logger"
+	"pinstack-api-gateway/internal/media"
+	"pinstack-api-gateway/internal/relationship"
+	"pinstack-api-gateway/internal/userview"
+	"time"
+)
+
+// Options configures how a profile is assembled.
+type Options struct {
+	SectionTimeout time.Duration
+	RecentPosts    int
+}
+
+type ProfileHandler struct {
+	userClient     user_client.UserClient
+	relationClient relation_client.RelationClient
+	postClient     post_client.PostClient
+	relationships  *relationship.Resolver
+	views          *userview.Policy
+	mediaURLs      *media.URLResolver
+	opts           Options
+	log            *logger.Logger
+}
+
+func NewProfileHandler(userClient user_client.UserClient, relationClient relation_client.RelationClient, postClient post_client.PostClient, relationships *relationship.Resolver, views *userview.Policy, mediaURLs *media.URLResolver, opts Options, log *logger.Logger) *ProfileHandler {
+	if opts.SectionTimeout <= 0 {
+		opts.SectionTimeout = 2 * time.Second
+	}
+	if opts.RecentPosts <= 0 {
+		opts.RecentPosts = 10
+	}
+	return &ProfileHandler{
+		userClient:     userClient,
+		relationClient: relationClient,
+		postClient:     postClient,
+		relationships:  relationships,
+		views:          views,
+		mediaURLs:      mediaURLs,
+		opts:           opts,
+		log:            log,
+	}
+}
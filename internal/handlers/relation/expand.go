@@ -0,0 +1,77 @@
+package relation_handler
+
+import (
+	"context"
+	"errors"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"log/slog"
+	"net/url"
+	"pinstack-api-gateway/internal/middlewares"
+	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/selection"
+	"pinstack-api-gateway/internal/utils"
+)
+
+// expandProfile adds full_name and bio to follower and followee entries.
+const expandProfile = "profile"
+
+// userLookupConcurrency bounds the per-entry user service calls made for one list.
+const userLookupConcurrency = 8
+
+var relationExpansions = []string{expandProfile}
+
+// parseRelationSelection reads ?fields= and ?expand=. The profile expansion is opt-in.
+func parseRelationSelection(query url.Values) (*selection.Selection, error) {
+	return selection.Parse(query, relationExpansions, nil)
+}
+
+// avatarURL returns the avatar to show for a user: their own, or the identicon served by the avatar endpoint.
+func (h *RelationHandler) avatarURL(avatarURL *string, userID int64, username string) *string {
+	return h.mediaURLs.PublicPtr(h.avatars.Or(avatarURL, userID, username))
+}
+
+// decorateUsers expands profile fields on request, points entries without an avatar at their identicon and, for authenticated callers, marks which
+// entries the caller follows. Failures only leave fields unset.
+func (h *RelationHandler) decorateUsers(ctx context.Context, users []*models.RelationUser, expand bool) {
+	if expand {
+		utils.FanOut(ctx, len(users), userLookupConcurrency, func(ctx context.Context, i int) error {
+			u := users[i]
+			user, err := h.userClient.GetUser(ctx, u.ID)
+			if err != nil {
+				if !errors.Is(err, custom_errors.ErrUserNotFound) {
+					h.log.Warn("Failed to look up relation user", slog.Int64("id", u.ID), slog.String("error", err.Error()))
+				}
+				return nil
+			}
+			u.FullName = user.FullName
+			u.Bio = user.Bio
+			return nil
+		})
+	}
+
+	for _, u := range users {
+		u.AvatarURL = h.avatarURL(u.AvatarURL, u.ID, u.Username)
+	}
+
+	claims, err := middlewares.GetClaimsFromContext(ctx)
+	if err != nil {
+		return
+	}
+	ids := make([]int64, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	statuses, err := h.relationships.StatusMany(ctx, claims.UserID, ids)
+	if err != nil {
+		h.log.Warn("Failed to resolve is_followed_by_me", slog.Int64("user_id", claims.UserID), slog.String("error", err.Error()))
+		return
+	}
+	for _, u := range users {
+		status := statuses[u.ID]
+		if status.Unknown && !status.Following {
+			continue
+		}
+		following := status.Following
+		u.IsFollowedByMe = &following
+	}
+}
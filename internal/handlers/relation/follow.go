@@ -80,6 +80,12 @@ func (h *RelationHandler) Follow(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	h.relationships.Invalidate(claims.UserID, req.FolloweeID)
+	h.suggester.Invalidate(claims.UserID)
+	if h.followHook != nil {
+		h.followHook.AfterFollow(claims.UserID, req.FolloweeID)
+	}
+
 	response := FollowResponse{
 		Message: "Followed successfully",
 	}
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"net/http"
 	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/pagination"
 	"pinstack-api-gateway/internal/utils"
 	"strconv"
 
@@ -14,21 +15,24 @@ import (
 
 type GetFolloweesResponse struct {
 	Followees []*models.RelationUser `json:"followees"`
-	Total     int64                  `json:"total"`
-	Page      int32                  `json:"page"`
-	Limit     int32                  `json:"limit"`
+	pagination.Meta
 }
 
 // GetFollowees godoc
 // @Summary Get user followees
 // @Description Get list of users that the user is following
+// @Description With a bearer token each entry also carries is_followed_by_me for the caller.
 // @Tags relation
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param user_id path int true "User ID"
-// @Param limit query int false "Limit for pagination" default(20)
-// @Param page query int false "Page number" default(1)
+// @Param page query int false "Page number, starting at 1" default(1)
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param fields query string false "Comma separated fields to return for each entry, e.g. id,username"
+// @Param expand query string false "Set to profile to include full_name and bio" Enums(profile)
 // @Success 200 {object} GetFolloweesResponse "Followees retrieved successfully"
+// @Header 200 {string} Link "RFC 8288 pagination links"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 404 {object} map[string]string "User not found"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -48,24 +52,21 @@ func (h *RelationHandler) GetFollowees(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limitStr := r.URL.Query().Get("limit")
-	pageStr := r.URL.Query().Get("page")
-
-	limit := int32(20) // default
-	if limitStr != "" {
-		if l, err := strconv.ParseInt(limitStr, 10, 32); err == nil && l > 0 && l <= 100 {
-			limit = int32(l)
-		}
+	params, err := pagination.Parse(r.URL.Query())
+	if err != nil || !params.PageAligned() {
+		h.log.Debug("Invalid pagination parameters", slog.String("query", r.URL.RawQuery))
+		utils.SendError(w, http.StatusBadRequest, pagination.ErrInvalidPagination.Error())
+		return
 	}
 
-	page := int32(1) // default
-	if pageStr != "" {
-		if p, err := strconv.ParseInt(pageStr, 10, 32); err == nil && p > 0 {
-			page = int32(p)
-		}
+	sel, err := parseRelationSelection(r.URL.Query())
+	if err != nil {
+		h.log.Debug("Invalid fields or expand parameter", slog.String("query", r.URL.RawQuery))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+		return
 	}
 
-	followees, total, err := h.relationClient.GetFollowees(r.Context(), userID, limit, page)
+	followees, total, err := h.relationClient.GetFollowees(r.Context(), userID, int32(params.Limit), int32(params.Page))
 	if err != nil {
 		h.log.Error("Failed to get followees", slog.Int64("user_id", userID), slog.String("error", err.Error()))
 
@@ -85,11 +86,12 @@ func (h *RelationHandler) GetFollowees(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	h.decorateUsers(r.Context(), followees, sel.Requested(expandProfile))
+
 	response := GetFolloweesResponse{
 		Followees: followees,
-		Total:     total,
-		Page:      page,
-		Limit:     limit,
+		Meta:      params.Meta(total, len(followees)),
 	}
-	utils.Send(w, http.StatusOK, response)
+	params.SetLinkHeader(w, r, response.Meta)
+	utils.SendSelected(w, http.StatusOK, sel, response, "followees")
 }
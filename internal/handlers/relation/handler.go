@@ -1,18 +1,40 @@
 package relation_handler
 
 import (
+	"pinstack-api-gateway/internal/avatar"
 	relation_client "pinstack-api-gateway/internal/clients/relation"
+	user_client "pinstack-api-gateway/internal/clients/user"
 	"pinstack-api-gateway/internal/logger"
+	"pinstack-api-gateway/internal/media"
+	"pinstack-api-gateway/internal/relationship"
 )
 
+// FollowHook runs after a follow or unfollow succeeds. Implementations must not block the request.
+type FollowHook interface {
+	AfterFollow(followerID, followeeID int64)
+	AfterUnfollow(followerID, followeeID int64)
+}
+
 type RelationHandler struct {
 	relationClient relation_client.RelationClient
+	userClient     user_client.UserClient
+	relationships  *relationship.Resolver
+	suggester      *relationship.Suggester
+	followHook     FollowHook
+	avatars        *avatar.Links
+	mediaURLs      *media.URLResolver
 	log            *logger.Logger
 }
 
-func NewRelationHandler(relationClient relation_client.RelationClient, log *logger.Logger) *RelationHandler {
+func NewRelationHandler(relationClient relation_client.RelationClient, userClient user_client.UserClient, relationships *relationship.Resolver, suggester *relationship.Suggester, followHook FollowHook, avatars *avatar.Links, mediaURLs *media.URLResolver, log *logger.Logger) *RelationHandler {
 	return &RelationHandler{
 		relationClient: relationClient,
+		userClient:     userClient,
+		relationships:  relationships,
+		suggester:      suggester,
+		followHook:     followHook,
+		avatars:        avatars,
+		mediaURLs:      mediaURLs,
 		log:            log,
 	}
 }
@@ -0,0 +1,145 @@
+package relation_handler
+
+import (
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"log/slog"
+	"net/http"
+	"pinstack-api-gateway/internal/middlewares"
+	"pinstack-api-gateway/internal/utils"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const maxStatusTargets = 100
+
+type RelationshipStatusResponse struct {
+	UserID     int64 `json:"user_id"`
+	Following  bool  `json:"following"`
+	FollowedBy bool  `json:"followed_by"`
+	Mutual     bool  `json:"mutual"`
+	// Unknown is set when the relationship could not be confirmed, so a false following or followed_by may be wrong.
+	Unknown bool `json:"unknown,omitempty"`
+}
+
+type RelationshipStatusesResponse struct {
+	Statuses []RelationshipStatusResponse `json:"statuses"`
+}
+
+// GetStatus godoc
+// @Summary Get relationship status
+// @Description Check whether the caller follows the user, is followed by them, or both
+// @Tags relation
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path int true "Target user ID"
+// @Success 200 {object} RelationshipStatusResponse "Relationship status"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /relation/{user_id}/status [get]
+func (h *RelationHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	userIDStr := chi.URLParam(r, "user_id")
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		h.log.Debug("Failed to parse user ID", slog.String("user_id", userIDStr), slog.String("error", err.Error()))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrInvalidInput.Error())
+		return
+	}
+	if userID <= 0 {
+		h.log.Debug("Invalid user ID", slog.Int64("user_id", userID))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+		return
+	}
+
+	statuses, ok := h.resolveStatuses(w, r, []int64{userID})
+	if !ok {
+		return
+	}
+	utils.Send(w, http.StatusOK, statuses[0])
+}
+
+// GetStatuses godoc
+// @Summary Get relationship status for several users
+// @Description Batch form of the relationship status check for up to 100 users.
+// @Description Statuses that could not be confirmed within the scan limits are marked unknown.
+// @Tags relation
+// @Produce json
+// @Security BearerAuth
+// @Param ids query string true "Comma separated target user IDs (max 100)"
+// @Success 200 {object} RelationshipStatusesResponse "Relationship statuses in request order"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /relation/status [get]
+func (h *RelationHandler) GetStatuses(w http.ResponseWriter, r *http.Request) {
+	ids, err := parseUserIDs(r.URL.Query().Get("ids"))
+	if err != nil {
+		h.log.Debug("Invalid ids parameter", slog.String("ids", r.URL.Query().Get("ids")))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrInvalidInput.Error())
+		return
+	}
+	if len(ids) == 0 || len(ids) > maxStatusTargets {
+		h.log.Debug("Wrong number of ids", slog.Int("count", len(ids)))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+		return
+	}
+
+	statuses, ok := h.resolveStatuses(w, r, ids)
+	if !ok {
+		return
+	}
+	utils.Send(w, http.StatusOK, RelationshipStatusesResponse{Statuses: statuses})
+}
+
+func (h *RelationHandler) resolveStatuses(w http.ResponseWriter, r *http.Request, ids []int64) ([]RelationshipStatusResponse, bool) {
+	claims, err := middlewares.GetClaimsFromContext(r.Context())
+	if err != nil {
+		h.log.Debug("No user claims in context", slog.String("error", err.Error()))
+		utils.SendError(w, http.StatusUnauthorized, custom_errors.ErrUnauthenticated.Error())
+		return nil, false
+	}
+
+	statuses, err := h.relationships.StatusMany(r.Context(), claims.UserID, ids)
+	if err != nil {
+		h.log.Error("Failed to resolve relationship status", slog.Int64("user_id", claims.UserID), slog.String("error", err.Error()))
+		utils.SendError(w, http.StatusInternalServerError, custom_errors.ErrExternalServiceError.Error())
+		return nil, false
+	}
+
+	resp := make([]RelationshipStatusResponse, len(ids))
+	for i, id := range ids {
+		status := statuses[id]
+		resp[i] = RelationshipStatusResponse{
+			UserID:     id,
+			Following:  status.Following,
+			FollowedBy: status.FollowedBy,
+			Mutual:     status.Mutual,
+			Unknown:    status.Unknown,
+		}
+	}
+	return resp, true
+}
+
+// parseUserIDs parses a comma separated ID list, dropping duplicates while keeping order.
+func parseUserIDs(value string) ([]int64, error) {
+	ids := make([]int64, 0)
+	seen := make(map[int64]struct{})
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, custom_errors.ErrInvalidInput
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,140 @@
+package relation_handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"log/slog"
+	"net/http"
+	"pinstack-api-gateway/internal/middlewares"
+	"pinstack-api-gateway/internal/relationship"
+	"pinstack-api-gateway/internal/selection"
+	"pinstack-api-gateway/internal/utils"
+	"strconv"
+)
+
+const defaultSuggestionsLimit = 10
+
+type SuggestionsResponse struct {
+	Suggestions []SuggestedUser `json:"suggestions"`
+}
+
+type SuggestedUser struct {
+	ID          int64   `json:"id"`
+	Username    string  `json:"username"`
+	FullName    *string `json:"full_name,omitempty"`
+	AvatarURL   *string `json:"avatar_url,omitempty"`
+	MutualCount int     `json:"mutual_count"`
+	Reason      string  `json:"reason"`
+}
+
+// GetSuggestions godoc
+// @Summary Get follow suggestions
+// @Description Suggest users followed by the people the caller follows, ranked by number of mutual connections
+// @Tags relation
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of suggestions (max 50)" default(10)
+// @Param fields query string false "Comma separated fields to return for each suggestion, e.g. id,username"
+// @Success 200 {object} SuggestionsResponse "Follow suggestions"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /relation/suggestions [get]
+func (h *RelationHandler) GetSuggestions(w http.ResponseWriter, r *http.Request) {
+	claims, err := middlewares.GetClaimsFromContext(r.Context())
+	if err != nil {
+		h.log.Debug("No user claims in context", slog.String("error", err.Error()))
+		utils.SendError(w, http.StatusUnauthorized, custom_errors.ErrUnauthenticated.Error())
+		return
+	}
+
+	limit := defaultSuggestionsLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > relationship.MaxSuggestions {
+			h.log.Debug("Invalid limit", slog.String("limit", limitStr))
+			utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+			return
+		}
+	}
+	sel, err := selection.Parse(r.URL.Query(), nil, nil)
+	if err != nil {
+		h.log.Debug("Invalid fields parameter", slog.String("query", r.URL.RawQuery))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+		return
+	}
+
+	suggestions, err := h.suggester.Suggest(r.Context(), claims.UserID)
+	if err != nil {
+		h.log.Error("Failed to compute suggestions", slog.Int64("user_id", claims.UserID), slog.String("error", err.Error()))
+		switch {
+		case errors.Is(err, custom_errors.ErrUserNotFound):
+			utils.SendError(w, http.StatusNotFound, custom_errors.ErrUserNotFound.Error())
+		default:
+			utils.SendError(w, http.StatusInternalServerError, custom_errors.ErrExternalServiceError.Error())
+		}
+		return
+	}
+
+	resp := SuggestionsResponse{Suggestions: make([]SuggestedUser, 0, limit)}
+	// Suggested users who no longer exist are dropped, so enrich in rounds until the page is full.
+	for next := 0; len(resp.Suggestions) < limit && next < len(suggestions); {
+		batch := suggestions[next:min(next+limit-len(resp.Suggestions), len(suggestions))]
+		next += len(batch)
+
+		items := make([]SuggestedUser, len(batch))
+		gone := make([]bool, len(batch))
+		errs := utils.FanOut(r.Context(), len(batch), userLookupConcurrency, func(ctx context.Context, i int) error {
+			s := batch[i]
+			items[i] = SuggestedUser{
+				ID:          s.UserID,
+				Username:    s.Username,
+				AvatarURL:   s.AvatarURL,
+				MutualCount: s.MutualCount,
+				Reason:      suggestionReason(s),
+			}
+			user, err := h.userClient.GetUser(ctx, s.UserID)
+			switch {
+			case err == nil:
+				items[i].Username = user.Username
+				items[i].FullName = user.FullName
+				items[i].AvatarURL = user.AvatarURL
+			case errors.Is(err, custom_errors.ErrUserNotFound):
+				h.log.Debug("Suggested user no longer exists", slog.Int64("user_id", s.UserID))
+				gone[i] = true
+			default:
+				h.log.Warn("Failed to enrich suggestion, using relation data", slog.Int64("user_id", s.UserID), slog.String("error", err.Error()))
+			}
+			return nil
+		})
+		for i, item := range items {
+			if errs[i] != nil {
+				h.log.Debug("Suggestions request cancelled", slog.String("error", errs[i].Error()))
+				return
+			}
+			if gone[i] {
+				continue
+			}
+			item.AvatarURL = h.avatarURL(item.AvatarURL, item.ID, item.Username)
+			resp.Suggestions = append(resp.Suggestions, item)
+		}
+	}
+
+	utils.SendSelected(w, http.StatusOK, sel, resp, "suggestions")
+}
+
+func suggestionReason(s relationship.Suggestion) string {
+	if len(s.Via) == 0 {
+		return ""
+	}
+	switch others := s.MutualCount - 1; others {
+	case 0:
+		return fmt.Sprintf("Followed by %s", s.Via[0])
+	case 1:
+		return fmt.Sprintf("Followed by %s and 1 other", s.Via[0])
+	default:
+		return fmt.Sprintf("Followed by %s and %d others", s.Via[0], others)
+	}
+}
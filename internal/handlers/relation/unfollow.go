@@ -79,6 +79,12 @@ func (h *RelationHandler) Unfollow(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	h.relationships.Invalidate(claims.UserID, req.FolloweeID)
+	h.suggester.Invalidate(claims.UserID)
+	if h.followHook != nil {
+		h.followHook.AfterUnfollow(claims.UserID, req.FolloweeID)
+	}
+
 	response := UnfollowResponse{
 		Message: "Unfollowed successfully",
 	}
@@ -0,0 +1,54 @@
+package share_handler
+
+import (
+	"pinstack-api-gateway/internal/avatar"
+	post_client "pinstack-api-gateway/internal/clients/post"
+	user_client "pinstack-api-gateway/internal/clients/user"
+	"pinstack-api-gateway/internal/logger"
+	"pinstack-api-gateway/internal/media"
+	"strings"
+	"time"
+)
+
+// Options configures the public share pages.
+type Options struct {
+	// BaseURL is the public URL of the gateway that share links are built on.
+	BaseURL  string
+	SiteName string
+	// CacheTTL is how long a built preview is reused before the post is fetched again.
+	CacheTTL time.Duration
+	// MaxAge is the Cache-Control max-age sent to clients and crawlers.
+	MaxAge time.Duration
+}
+
+type ShareHandler struct {
+	postClient post_client.PostClient
+	userClient user_client.UserClient
+	mediaURLs  *media.URLResolver
+	avatars    *avatar.Links
+	previews   *previewCache
+	opts       Options
+	log        *logger.Logger
+}
+
+func NewShareHandler(postClient post_client.PostClient, userClient user_client.UserClient, mediaURLs *media.URLResolver, avatars *avatar.Links, opts Options, log *logger.Logger) *ShareHandler {
+	opts.BaseURL = strings.TrimRight(opts.BaseURL, "/")
+	if opts.SiteName == "" {
+		opts.SiteName = "Pinstack"
+	}
+	if opts.CacheTTL <= 0 {
+		opts.CacheTTL = time.Minute
+	}
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = 5 * time.Minute
+	}
+	return &ShareHandler{
+		postClient: postClient,
+		userClient: userClient,
+		mediaURLs:  mediaURLs,
+		avatars:    avatars,
+		previews:   newPreviewCache(opts.CacheTTL),
+		opts:       opts,
+		log:        log,
+	}
+}
@@ -0,0 +1,155 @@
+package share_handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"pinstack-api-gateway/internal/utils"
+	"strconv"
+	"strings"
+)
+
+const defaultEmbedWidth = 550
+
+// embedTemplate is the HTML consumers embed for a post. It is escaped like the share page.
+var embedTemplate = template.Must(template.New("embed").Parse(
+	`<blockquote class="pinstack-post" cite="{{.URL}}"><p>{{.Description}}</p>&mdash; {{.AuthorName}} (@{{.AuthorUsername}}) <a href="{{.URL}}">{{.Title}}</a></blockquote>`))
+
+// OEmbedResponse follows the oEmbed 1.0 rich type. Height is null because the embed sizes to its content.
+type OEmbedResponse struct {
+	Version      string `json:"version"`
+	Type         string `json:"type"`
+	Title        string `json:"title"`
+	AuthorName   string `json:"author_name"`
+	ProviderName string `json:"provider_name"`
+	ProviderURL  string `json:"provider_url"`
+	CacheAge     int    `json:"cache_age"`
+	HTML         string `json:"html"`
+	Width        int    `json:"width"`
+	Height       *int   `json:"height"`
+}
+
+// OEmbed godoc
+// @Summary Get the oEmbed description of a share link
+// @Description oEmbed 1.0 rich response for a post share page URL of this gateway. Only the JSON format is supported,
+// @Description and the body is the bare oEmbed object rather than the API response envelope.
+// @Description Served at the site root as /oembed, outside /api/v1. Supports conditional requests with If-None-Match and If-Modified-Since.
+// @Tags share
+// @Produce json
+// @Param url query string true "Share page URL, e.g. https://example.com/p/42"
+// @Param format query string false "Response format" Enums(json)
+// @Param maxwidth query int false "Maximum embed width in pixels"
+// @Param maxheight query int false "Maximum embed height in pixels"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Param If-Modified-Since header string false "Last-Modified from a previous response"
+// @Success 200 {object} OEmbedResponse "oEmbed description"
+// @Success 304 "Not modified"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "Post not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 501 {object} map[string]string "Unsupported format"
+// @Router /oembed [get]
+func (h *ShareHandler) OEmbed(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if format := query.Get("format"); format != "" && format != "json" {
+		utils.SendError(w, http.StatusNotImplemented, "unsupported format")
+		return
+	}
+	id, ok := h.postIDFromURL(query.Get("url"))
+	if !ok {
+		h.log.Debug("oEmbed url is not a share link", slog.String("url", query.Get("url")))
+		utils.SendError(w, http.StatusNotFound, custom_errors.ErrPostNotFound.Error())
+		return
+	}
+	width := defaultEmbedWidth
+	if s := query.Get("maxwidth"); s != "" {
+		maxWidth, err := strconv.Atoi(s)
+		if err != nil || maxWidth <= 0 {
+			utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+			return
+		}
+		width = min(width, maxWidth)
+	}
+	if s := query.Get("maxheight"); s != "" {
+		if maxHeight, err := strconv.Atoi(s); err != nil || maxHeight <= 0 {
+			utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+			return
+		}
+	}
+
+	p, err := h.loadPreview(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, custom_errors.ErrPostNotFound):
+			utils.SendError(w, http.StatusNotFound, custom_errors.ErrPostNotFound.Error())
+		default:
+			h.log.Error("Failed to load post preview", slog.Int64("id", id), slog.String("error", err.Error()))
+			utils.SendError(w, http.StatusInternalServerError, custom_errors.ErrExternalServiceError.Error())
+		}
+		return
+	}
+
+	h.setCacheHeaders(w)
+	if utils.CheckNotModified(w, r, utils.ResourceETag(p.etag, "oembed", strconv.Itoa(width)), p.ModifiedAt) {
+		return
+	}
+
+	var embed bytes.Buffer
+	if err := embedTemplate.Execute(&embed, p); err != nil {
+		h.log.Error("Failed to render oEmbed html", slog.Int64("id", id), slog.String("error", err.Error()))
+		utils.SendError(w, http.StatusInternalServerError, "failed to render embed")
+		return
+	}
+	resp := OEmbedResponse{
+		Version:      "1.0",
+		Type:         "rich",
+		Title:        p.Title,
+		AuthorName:   p.AuthorName,
+		ProviderName: h.opts.SiteName,
+		ProviderURL:  h.opts.BaseURL,
+		CacheAge:     int(h.opts.MaxAge.Seconds()),
+		HTML:         embed.String(),
+		Width:        width,
+	}
+
+	// oEmbed consumers expect the bare object rather than the API response envelope.
+	body, err := json.Marshal(resp)
+	if err != nil {
+		h.log.Error("Failed to encode oEmbed response", slog.String("error", err.Error()))
+		utils.SendError(w, http.StatusInternalServerError, "failed to encode response")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// postIDFromURL extracts the post ID from a share page URL of this gateway.
+func (h *ShareHandler) postIDFromURL(raw string) (int64, bool) {
+	if raw == "" {
+		return 0, false
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return 0, false
+	}
+	base, err := url.Parse(h.opts.BaseURL)
+	if err != nil || !strings.EqualFold(u.Host, base.Host) {
+		return 0, false
+	}
+	rest, ok := strings.CutPrefix(u.Path, strings.TrimRight(base.Path, "/")+"/p/")
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(strings.TrimSuffix(rest, "/"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,136 @@
+package share_handler
+
+import (
+	"bytes"
+	"errors"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"pinstack-api-gateway/internal/utils"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// pageTemplate is the share page. html/template escapes every value for its context,
+// so post and author text cannot break out of attributes or inject markup.
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} · {{.SiteName}}</title>
+<link rel="canonical" href="{{.URL}}">
+<link rel="alternate" type="application/json+oembed" href="{{.OEmbedURL}}" title="{{.Title}}">
+<meta name="description" content="{{.Description}}">
+<meta name="author" content="{{.AuthorName}}">
+<meta property="og:type" content="article">
+<meta property="og:site_name" content="{{.SiteName}}">
+<meta property="og:url" content="{{.URL}}">
+<meta property="og:title" content="{{.Title}}">
+<meta property="og:description" content="{{.Description}}">
+<meta property="og:image" content="{{.Image}}">
+<meta property="article:published_time" content="{{.PublishedAt}}">
+<meta property="article:modified_time" content="{{.ModifiedAt}}">
+<meta name="twitter:card" content="{{.Card}}">
+<meta name="twitter:title" content="{{.Title}}">
+<meta name="twitter:description" content="{{.Description}}">
+<meta name="twitter:image" content="{{.Image}}">
+</head>
+<body>
+<article>
+<h1>{{.Title}}</h1>
+<p>{{.AuthorName}} (@{{.AuthorUsername}})</p>
+{{if .ImageURL}}<img src="{{.ImageURL}}" alt="{{.Title}}">
+{{end}}<p>{{.Description}}</p>
+</article>
+</body>
+</html>
+`))
+
+type pageData struct {
+	*preview
+	SiteName  string
+	OEmbedURL string
+	// Image is the post image, or the author's avatar when the post has none.
+	Image       string
+	Card        string
+	PublishedAt string
+	ModifiedAt  string
+}
+
+// Page godoc
+// @Summary Get a post share page
+// @Description Minimal HTML page for a post with OpenGraph and Twitter card metadata, so links to it unfurl into previews
+// @Description in chat apps and social networks. It links its oEmbed description for consumers that support discovery.
+// @Description Served at the site root as /p/{id}, outside /api/v1. Supports conditional requests with If-None-Match and If-Modified-Since.
+// @Tags share
+// @Produce html
+// @Param id path int true "Post ID"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Param If-Modified-Since header string false "Last-Modified from a previous response"
+// @Success 200 {string} string "Share page"
+// @Success 304 "Not modified"
+// @Failure 404 {string} string "Post not found"
+// @Failure 500 {string} string "Internal server error"
+// @Failure 502 {string} string "Post service unavailable"
+// @Router /p/{id} [get]
+func (h *ShareHandler) Page(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		h.log.Debug("Invalid post id for share page", slog.String("id", idStr))
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	p, err := h.loadPreview(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, custom_errors.ErrPostNotFound):
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		default:
+			h.log.Error("Failed to load post preview", slog.Int64("id", id), slog.String("error", err.Error()))
+			http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+		}
+		return
+	}
+
+	h.setCacheHeaders(w)
+	if utils.CheckNotModified(w, r, utils.ResourceETag(p.etag, "html"), p.ModifiedAt) {
+		return
+	}
+
+	data := pageData{
+		preview:     p,
+		SiteName:    h.opts.SiteName,
+		OEmbedURL:   h.opts.BaseURL + "/oembed?" + url.Values{"url": {p.URL}, "format": {"json"}}.Encode(),
+		Image:       p.ImageURL,
+		Card:        "summary_large_image",
+		PublishedAt: p.PublishedAt.UTC().Format(time.RFC3339),
+		ModifiedAt:  p.ModifiedAt.UTC().Format(time.RFC3339),
+	}
+	if data.Image == "" {
+		data.Image, data.Card = p.AvatarURL, "summary"
+	}
+
+	var buf bytes.Buffer
+	if err := pageTemplate.Execute(&buf, data); err != nil {
+		h.log.Error("Failed to render share page", slog.Int64("id", id), slog.String("error", err.Error()))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	// The page needs no scripts or styles; only images may load.
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; img-src https: http:")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
+
+func (h *ShareHandler) setCacheHeaders(w http.ResponseWriter) {
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(h.opts.MaxAge.Seconds())))
+}
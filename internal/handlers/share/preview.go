@@ -0,0 +1,192 @@
+package share_handler
+
+import (
+	"context"
+	"errors"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"net/url"
+	"pinstack-api-gateway/internal/content"
+	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/utils"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	descriptionMaxRunes = 200
+	previewCacheMaxSize = 10000
+	identiconImageSize  = 256
+)
+
+// preview is what link previews show of a post. Every field is plain text or a URL;
+// the templates escape them for the context they are written to.
+type preview struct {
+	PostID         int64
+	URL            string
+	Title          string
+	Description    string
+	AuthorName     string
+	AuthorUsername string
+	// ImageURL is the first image attached to the post; AvatarURL the author's avatar, used when there is none.
+	ImageURL    string
+	AvatarURL   string
+	PublishedAt time.Time
+	ModifiedAt  time.Time
+
+	etag string
+}
+
+// previewCache keeps built previews for a short while, so a link shared into a busy chat
+// does not fetch the post and its author once per crawler.
+type previewCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[int64]previewCacheEntry
+}
+
+type previewCacheEntry struct {
+	preview   *preview
+	expiresAt time.Time
+}
+
+func newPreviewCache(ttl time.Duration) *previewCache {
+	return &previewCache{ttl: ttl, entries: make(map[int64]previewCacheEntry)}
+}
+
+func (c *previewCache) get(id int64) (*preview, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[id]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.preview, true
+}
+
+func (c *previewCache) put(id int64, p *preview) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if len(c.entries) >= previewCacheMaxSize {
+		for k, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= previewCacheMaxSize {
+			c.entries = make(map[int64]previewCacheEntry)
+		}
+	}
+	c.entries[id] = previewCacheEntry{preview: p, expiresAt: now.Add(c.ttl)}
+}
+
+// loadPreview returns the preview of a post, from the cache when possible.
+// It fails with custom_errors.ErrPostNotFound when the post does not exist.
+func (h *ShareHandler) loadPreview(ctx context.Context, id int64) (*preview, error) {
+	if p, ok := h.previews.get(id); ok {
+		return p, nil
+	}
+
+	post, err := h.postClient.GetPostByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	author, err := h.userClient.GetUser(ctx, post.Post.AuthorID)
+	if err != nil {
+		if !errors.Is(err, custom_errors.ErrUserNotFound) {
+			return nil, err
+		}
+		author = utils.GenerateUnknownAuthor()
+	}
+
+	p := h.buildPreview(post, author)
+	h.previews.put(id, p)
+	return p, nil
+}
+
+func (h *ShareHandler) buildPreview(post *models.PostDetailed, author *models.User) *preview {
+	p := &preview{
+		PostID:         post.Post.ID,
+		URL:            h.postURL(post.Post.ID),
+		Title:          post.Post.Title,
+		AuthorName:     author.Username,
+		AuthorUsername: author.Username,
+		ImageURL:       webURL(h.firstImage(post.Media)),
+		AvatarURL:      webURL(h.avatarImage(author)),
+		PublishedAt:    post.Post.CreatedAt,
+		ModifiedAt:     post.Post.UpdatedAt,
+	}
+	if author.FullName != nil && *author.FullName != "" {
+		p.AuthorName = *author.FullName
+	}
+	if post.Post.Content != nil {
+		p.Description = content.Excerpt(*post.Post.Content, descriptionMaxRunes)
+	}
+	if p.Description == "" {
+		p.Description = "Post by " + p.AuthorName + " (@" + p.AuthorUsername + ")"
+	}
+	if author.UpdatedAt.After(p.ModifiedAt) {
+		p.ModifiedAt = author.UpdatedAt
+	}
+
+	parts := []string{
+		"share",
+		strconv.FormatInt(post.Post.ID, 10),
+		utils.VersionTag(post.Post.UpdatedAt),
+		strconv.FormatInt(author.ID, 10),
+		utils.VersionTag(author.UpdatedAt),
+	}
+	if h.mediaURLs.Signed() {
+		parts = append(parts, "media", utils.VersionTag(h.mediaURLs.Window()))
+	}
+	p.etag = utils.ResourceETag(parts...)
+	return p
+}
+
+// firstImage returns the URL of the first image attached to a post, preferring its medium sized variant.
+func (h *ShareHandler) firstImage(items []*models.PostMedia) string {
+	images := make([]*models.PostMedia, 0, len(items))
+	for _, m := range items {
+		if m.Type == models.MediaTypeImage && webURL(m.URL) != "" {
+			images = append(images, m)
+		}
+	}
+	if len(images) == 0 {
+		return ""
+	}
+	sort.SliceStable(images, func(i, j int) bool { return images[i].Position < images[j].Position })
+	if variants := h.mediaURLs.Variants(images[0].URL); variants != nil {
+		return variants.Medium
+	}
+	return h.mediaURLs.Public(images[0].URL)
+}
+
+// avatarImage returns the author's avatar, or a PNG identicon since previews do not render SVG images.
+func (h *ShareHandler) avatarImage(author *models.User) string {
+	if author.AvatarURL != nil && *author.AvatarURL != "" {
+		return h.mediaURLs.Public(*author.AvatarURL)
+	}
+	u := h.avatars.URL(author.ID, author.Username)
+	sep := "?"
+	if strings.Contains(u, "?") {
+		sep = "&"
+	}
+	return u + sep + "format=png&size=" + strconv.Itoa(identiconImageSize)
+}
+
+// webURL returns rawURL if it is an absolute http or https URL. Meta tag contents are not
+// URL-sanitised by the templates, so anything else is dropped rather than handed to crawlers.
+func webURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ""
+	}
+	return rawURL
+}
+
+func (h *ShareHandler) postURL(id int64) string {
+	return h.opts.BaseURL + "/p/" + strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,108 @@
+package syndication_handler
+
+import (
+	"encoding/xml"
+	"net/http"
+	"time"
+)
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomPerson  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published"`
+	Links      []atomLink     `xml:"link"`
+	Summary    string         `xml:"summary,omitempty"`
+	Content    *atomContent   `xml:"content"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// GetAtom godoc
+// @Summary Get a user's posts as Atom
+// @Description Atom 1.0 feed of the user's latest posts, newest first, with media as enclosure links.
+// @Description Supports conditional requests with If-None-Match and If-Modified-Since.
+// @Tags users
+// @Produce application/atom+xml
+// @Param id path int true "User ID"
+// @Param limit query int false "Number of posts (1-50)" default(20)
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Param If-Modified-Since header string false "Last-Modified from a previous response"
+// @Success 200 {string} string "Atom feed"
+// @Success 304 "Not modified"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /users/{id}/posts.atom [get]
+func (h *SyndicationHandler) GetAtom(w http.ResponseWriter, r *http.Request) {
+	f := h.loadFeed(w, r, "atom")
+	if f == nil {
+		return
+	}
+
+	doc := atomFeed{
+		// The feed URL is stable for the user, so it doubles as the feed's permanent ID.
+		ID:      f.SelfURL,
+		Title:   f.Title,
+		Updated: f.Updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.SelfURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: f.Link, Rel: "alternate", Type: "text/html"},
+		},
+		Author:  atomPerson{Name: f.Author.Username},
+		Entries: make([]atomEntry, 0, len(f.Entries)),
+	}
+	if f.Author.FullName != nil && *f.Author.FullName != "" {
+		doc.Author.Name = *f.Author.FullName
+	}
+	for _, e := range f.Entries {
+		entry := atomEntry{
+			ID:        e.Link,
+			Title:     e.Title,
+			Updated:   e.Updated.UTC().Format(time.RFC3339),
+			Published: e.Published.UTC().Format(time.RFC3339),
+			Links:     []atomLink{{Href: e.Link, Rel: "alternate", Type: "text/html"}},
+			Summary:   e.Summary,
+		}
+		if e.HTML != "" {
+			entry.Content = &atomContent{Type: "html", Value: e.HTML}
+		}
+		for _, m := range e.Media {
+			entry.Links = append(entry.Links, atomLink{Href: m.URL, Rel: "enclosure", Type: m.Type})
+		}
+		for _, t := range e.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: t})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	h.writeXML(w, "application/atom+xml; charset=utf-8", doc)
+}
@@ -0,0 +1,187 @@
+package syndication_handler
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"log/slog"
+	"net/http"
+	"pinstack-api-gateway/internal/content"
+	"pinstack-api-gateway/internal/media"
+	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/utils"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultFeedItems = 20
+	maxFeedItems     = 50
+	summaryMaxRunes  = 300
+	feedMaxAge       = 5 * time.Minute
+)
+
+// feed is the format independent content of a user's feed.
+type feed struct {
+	Author  *models.User
+	Title   string
+	Link    string
+	SelfURL string
+	Updated time.Time
+	Entries []feedEntry
+}
+
+type feedEntry struct {
+	ID        int64
+	Title     string
+	Link      string
+	Summary   string
+	HTML      string
+	Tags      []string
+	Media     []feedMedia
+	Published time.Time
+	Updated   time.Time
+}
+
+type feedMedia struct {
+	URL  string
+	Type string
+}
+
+// loadFeed reads the user and their latest posts for the request, answering the request itself
+// when it fails or when the client's copy is still fresh. It returns nil in both cases.
+func (h *SyndicationHandler) loadFeed(w http.ResponseWriter, r *http.Request, format string) *feed {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		h.log.Debug("Invalid user id for feed", slog.String("id", idStr))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrInvalidInput.Error())
+		return nil
+	}
+	limit := defaultFeedItems
+	if s := r.URL.Query().Get("limit"); s != "" {
+		limit, err = strconv.Atoi(s)
+		if err != nil || limit < 1 || limit > maxFeedItems {
+			h.log.Debug("Invalid feed limit", slog.String("limit", s))
+			utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+			return nil
+		}
+	}
+
+	author, err := h.userClient.GetUser(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, custom_errors.ErrUserNotFound):
+			utils.SendError(w, http.StatusNotFound, custom_errors.ErrUserNotFound.Error())
+		default:
+			h.log.Error("Failed to get feed user", slog.Int64("id", id), slog.String("error", err.Error()))
+			utils.SendError(w, http.StatusInternalServerError, custom_errors.ErrExternalServiceError.Error())
+		}
+		return nil
+	}
+
+	offset := 0
+	posts, _, err := h.postClient.ListPosts(r.Context(), &models.PostFilters{AuthorID: &id, Offset: &offset, Limit: &limit})
+	if err != nil {
+		h.log.Error("Failed to list feed posts", slog.Int64("author_id", id), slog.String("error", err.Error()))
+		utils.SendError(w, http.StatusInternalServerError, custom_errors.ErrExternalServiceError.Error())
+		return nil
+	}
+	sort.SliceStable(posts, func(i, j int) bool {
+		return posts[i].Post.CreatedAt.After(posts[j].Post.CreatedAt)
+	})
+
+	updated := author.UpdatedAt
+	parts := []string{"feed", format, strconv.FormatInt(id, 10), utils.VersionTag(author.UpdatedAt), strconv.Itoa(limit)}
+	for _, p := range posts {
+		if p.Post.UpdatedAt.After(updated) {
+			updated = p.Post.UpdatedAt
+		}
+		parts = append(parts, strconv.FormatInt(p.Post.ID, 10), utils.VersionTag(p.Post.UpdatedAt))
+	}
+	if h.mediaURLs.Signed() {
+		parts = append(parts, "media", utils.VersionTag(h.mediaURLs.Window()))
+		if window := h.mediaURLs.Window(); window.After(updated) {
+			updated = window
+		}
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(feedMaxAge.Seconds())))
+	if utils.CheckNotModified(w, r, utils.ResourceETag(parts...), updated) {
+		return nil
+	}
+
+	name := author.Username
+	if author.FullName != nil && *author.FullName != "" {
+		name = *author.FullName
+	}
+	f := &feed{
+		Author:  author,
+		Title:   "Posts by " + name + " (@" + author.Username + ") · " + h.opts.SiteName,
+		Link:    h.opts.BaseURL,
+		SelfURL: h.opts.BaseURL + "/api/v1/users/" + strconv.FormatInt(id, 10) + "/posts." + format,
+		Updated: updated,
+		Entries: make([]feedEntry, 0, len(posts)),
+	}
+	for _, p := range posts {
+		f.Entries = append(f.Entries, h.feedEntry(p))
+	}
+	return f
+}
+
+func (h *SyndicationHandler) feedEntry(p *models.PostDetailed) feedEntry {
+	entry := feedEntry{
+		ID:        p.Post.ID,
+		Title:     p.Post.Title,
+		Link:      h.opts.BaseURL + "/p/" + strconv.FormatInt(p.Post.ID, 10),
+		Published: p.Post.CreatedAt,
+		Updated:   p.Post.UpdatedAt,
+	}
+	if p.Post.Content != nil {
+		entry.Summary = content.Excerpt(*p.Post.Content, summaryMaxRunes)
+		html, err := h.markdown.Render(p.Post.ID, p.Post.UpdatedAt, *p.Post.Content)
+		if err != nil {
+			h.log.Warn("Failed to render post content", slog.Int64("id", p.Post.ID), slog.String("error", err.Error()))
+		}
+		entry.HTML = html
+	}
+	for _, t := range p.Tags {
+		entry.Tags = append(entry.Tags, t.Name)
+	}
+
+	items := append([]*models.PostMedia(nil), p.Media...)
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Position < items[j].Position })
+	for _, m := range items {
+		entry.Media = append(entry.Media, feedMedia{URL: h.mediaURLs.Public(m.URL), Type: mediaType(m)})
+	}
+	return entry
+}
+
+// writeXML sends an XML document, encoding it fully first so that failures can still become an error response.
+func (h *SyndicationHandler) writeXML(w http.ResponseWriter, contentType string, doc any) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		h.log.Error("Failed to encode feed", slog.String("error", err.Error()))
+		utils.SendError(w, http.StatusInternalServerError, "failed to encode feed")
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// mediaType guesses the MIME type of an attachment from its extension. Enclosures must carry a type,
+// so unknown extensions are reported as opaque binary data.
+func mediaType(m *models.PostMedia) string {
+	if t := media.ContentTypeByURL(m.URL); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
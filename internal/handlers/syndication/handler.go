@@ -0,0 +1,44 @@
+package syndication_handler
+
+import (
+	post_client "pinstack-api-gateway/internal/clients/post"
+	user_client "pinstack-api-gateway/internal/clients/user"
+	"pinstack-api-gateway/internal/content"
+	"pinstack-api-gateway/internal/logger"
+	"pinstack-api-gateway/internal/media"
+	"strings"
+)
+
+// markdownCacheSize is how many rendered post versions are kept in memory.
+const markdownCacheSize = 2000
+
+// Options configures the links written into feeds.
+type Options struct {
+	// BaseURL is the public URL of the gateway; feed and post links are built on it.
+	BaseURL  string
+	SiteName string
+}
+
+type SyndicationHandler struct {
+	postClient post_client.PostClient
+	userClient user_client.UserClient
+	mediaURLs  *media.URLResolver
+	markdown   *content.MarkdownRenderer
+	opts       Options
+	log        *logger.Logger
+}
+
+func NewSyndicationHandler(postClient post_client.PostClient, userClient user_client.UserClient, mediaURLs *media.URLResolver, opts Options, log *logger.Logger) *SyndicationHandler {
+	opts.BaseURL = strings.TrimRight(opts.BaseURL, "/")
+	if opts.SiteName == "" {
+		opts.SiteName = "Pinstack"
+	}
+	return &SyndicationHandler{
+		postClient: postClient,
+		userClient: userClient,
+		mediaURLs:  mediaURLs,
+		markdown:   content.NewMarkdownRenderer(markdownCacheSize),
+		opts:       opts,
+		log:        log,
+	}
+}
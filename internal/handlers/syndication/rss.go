@@ -0,0 +1,109 @@
+package syndication_handler
+
+import (
+	"encoding/xml"
+	"net/http"
+	"time"
+)
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string      `xml:"title"`
+	Link          string      `xml:"link"`
+	Description   string      `xml:"description"`
+	SelfLink      rssAtomLink `xml:"atom:link"`
+	LastBuildDate string      `xml:"lastBuildDate"`
+	TTL           int         `xml:"ttl"`
+	Items         []rssItem   `xml:"item"`
+}
+
+type rssAtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string         `xml:"title"`
+	Link        string         `xml:"link"`
+	GUID        rssGUID        `xml:"guid"`
+	PubDate     string         `xml:"pubDate"`
+	Description string         `xml:"description,omitempty"`
+	Categories  []string       `xml:"category"`
+	Enclosures  []rssEnclosure `xml:"enclosure"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// rssEnclosure carries a post's media. The gateway does not know attachment sizes, and RSS
+// requires a length, so it is reported as 0 as readers expect for unknown sizes.
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// GetRSS godoc
+// @Summary Get a user's posts as RSS
+// @Description RSS 2.0 feed of the user's latest posts, newest first, with media as enclosures.
+// @Description Supports conditional requests with If-None-Match and If-Modified-Since.
+// @Tags users
+// @Produce application/rss+xml
+// @Param id path int true "User ID"
+// @Param limit query int false "Number of posts (1-50)" default(20)
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Param If-Modified-Since header string false "Last-Modified from a previous response"
+// @Success 200 {string} string "RSS feed"
+// @Success 304 "Not modified"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /users/{id}/posts.rss [get]
+func (h *SyndicationHandler) GetRSS(w http.ResponseWriter, r *http.Request) {
+	f := h.loadFeed(w, r, "rss")
+	if f == nil {
+		return
+	}
+
+	doc := rssFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   f.Title,
+			SelfLink:      rssAtomLink{Href: f.SelfURL, Rel: "self", Type: "application/rss+xml"},
+			LastBuildDate: f.Updated.UTC().Format(time.RFC1123Z),
+			TTL:           int(feedMaxAge.Minutes()),
+			Items:         make([]rssItem, 0, len(f.Entries)),
+		},
+	}
+	if f.Author.Bio != nil && *f.Author.Bio != "" {
+		doc.Channel.Description = *f.Author.Bio
+	}
+	for _, e := range f.Entries {
+		item := rssItem{
+			Title:       e.Title,
+			Link:        e.Link,
+			GUID:        rssGUID{IsPermaLink: true, Value: e.Link},
+			PubDate:     e.Published.UTC().Format(time.RFC1123Z),
+			Description: e.HTML,
+			Categories:  e.Tags,
+		}
+		for _, m := range e.Media {
+			item.Enclosures = append(item.Enclosures, rssEnclosure{URL: m.URL, Type: m.Type})
+		}
+		doc.Channel.Items = append(doc.Channel.Items, item)
+	}
+
+	h.writeXML(w, "application/rss+xml; charset=utf-8", doc)
+}
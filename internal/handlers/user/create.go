@@ -21,28 +21,21 @@ type CreateUserRequest struct {
 	AvatarURL *string `json:"avatar_url,omitempty" validate:"omitempty,url"`
 }
 
-type CreateUserResponse struct {
-	ID        int64   `json:"id"`
-	Username  string  `json:"username"`
-	Email     string  `json:"email"`
-	FullName  *string `json:"full_name,omitempty"`
-	Bio       *string `json:"bio,omitempty"`
-	AvatarURL *string `json:"avatar_url,omitempty"`
-	CreatedAt string  `json:"created_at"`
-	UpdatedAt string  `json:"updated_at"`
-}
-
 // CreateUser godoc
 // @Summary Create a new user
 // @Description Create a new user with provided data
+// @Description With Accept: application/x-protobuf the user is the user.v1.User message, which has no avatar_variants.
 // @Tags users
 // @Accept json
 // @Produce json
+// @Produce application/msgpack
+// @Produce application/x-protobuf
 // @Security BearerAuth
 // @Param request body CreateUserRequest true "User creation data"
-// @Success 201 {object} CreateUserResponse "User created successfully"
+// @Success 201 {object} userview.User "User created successfully"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 406 {object} map[string]string "Requested media type cannot represent the response"
 // @Failure 409 {object} map[string]string "User already exists"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /users [post]
@@ -89,16 +82,7 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := CreateUserResponse{
-		ID:        createdUser.ID,
-		Username:  createdUser.Username,
-		Email:     createdUser.Email,
-		FullName:  createdUser.FullName,
-		Bio:       createdUser.Bio,
-		AvatarURL: createdUser.AvatarURL,
-		CreatedAt: createdUser.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: createdUser.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-	}
+	response := h.userResponse(r, createdUser)
 
 	utils.Send(w, http.StatusCreated, response)
 }
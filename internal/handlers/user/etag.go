@@ -0,0 +1,14 @@
+package user_handler
+
+import (
+	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/utils"
+	"strconv"
+)
+
+// userETag identifies the version of a user representation.
+// variants distinguish alternative representations of the same version, such as ones with re-signed media URLs.
+func userETag(user *models.User, variants ...string) string {
+	version := []string{"user", strconv.FormatInt(user.ID, 10), utils.VersionTag(user.UpdatedAt)}
+	return utils.RepresentationETag(version, variants...)
+}
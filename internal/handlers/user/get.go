@@ -5,33 +5,34 @@ import (
 	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
 	"log/slog"
 	"net/http"
+	"pinstack-api-gateway/internal/selection"
 	"pinstack-api-gateway/internal/utils"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
 )
 
-type GetUserResponse struct {
-	ID        int64   `json:"id"`
-	Username  string  `json:"username"`
-	Email     string  `json:"email"`
-	FullName  *string `json:"full_name,omitempty"`
-	Bio       *string `json:"bio,omitempty"`
-	AvatarURL *string `json:"avatar_url,omitempty"`
-	CreatedAt string  `json:"created_at"`
-	UpdatedAt string  `json:"updated_at"`
-}
-
 // GetUser godoc
 // @Summary Get user by ID
 // @Description Get user information by user ID
+// @Description The email is only included for the account owner and administrators.
+// @Description Authentication is optional; a bearer token that is sent must be valid.
+// @Description With Accept: application/x-protobuf the user is the user.v1.User message, which has no avatar_variants.
+// @Description It cannot be combined with fields (406).
 // @Tags users
 // @Produce json
+// @Produce application/msgpack
+// @Produce application/x-protobuf
 // @Param id path string true "User ID"
-// @Success 200 {object} GetUserResponse "User information"
+// @Param fields query string false "Comma separated fields to return, e.g. username,avatar_url"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Param If-Modified-Since header string false "Last-Modified from a previous response"
+// @Success 200 {object} userview.User "User information"
+// @Success 304 "Not modified"
 // @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 401 {object} map[string]string "Invalid bearer token"
 // @Failure 404 {object} map[string]string "User not found"
+// @Failure 406 {object} map[string]string "Requested media type cannot represent the response"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /users/{id} [get]
 func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
@@ -47,6 +48,13 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	sel, err := selection.Parse(r.URL.Query(), nil, nil)
+	if err != nil {
+		h.log.Debug("Invalid fields parameter", slog.String("query", r.URL.RawQuery))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+		return
+	}
+
 	user, err := h.userClient.GetUser(r.Context(), id)
 	if err != nil {
 		switch {
@@ -58,16 +66,22 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := GetUserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		FullName:  user.FullName,
-		Bio:       user.Bio,
-		AvatarURL: user.AvatarURL,
-		CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	varyByCaller(w)
+	variants := []string{utils.CodecVariant(w), sel.Variant(), h.views.For(r.Context(), user.ID).String()}
+	lastModified := user.UpdatedAt
+	if h.mediaURLs.Signed() {
+		// The avatar URL is re-signed every window, so a cached copy is only fresh within one.
+		window := h.mediaURLs.Window()
+		variants = append(variants, "media", utils.VersionTag(window))
+		if window.After(lastModified) {
+			lastModified = window
+		}
 	}
+	if utils.CheckNotModified(w, r, userETag(user, variants...), lastModified) {
+		return
+	}
+
+	response := h.userResponse(r, user)
 
-	utils.Send(w, http.StatusOK, response)
+	utils.SendSelected(w, http.StatusOK, sel, response)
 }
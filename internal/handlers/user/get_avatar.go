@@ -0,0 +1,121 @@
+package user_handler
+
+import (
+	"errors"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"log/slog"
+	"net/http"
+	"pinstack-api-gateway/internal/avatar"
+	"pinstack-api-gateway/internal/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultIdenticonSize = 128
+	minIdenticonSize     = 16
+	maxIdenticonSize     = 512
+	// avatarRedirectMaxAge keeps clients from following a stale redirect for long after an avatar changes.
+	avatarRedirectMaxAge = 5 * time.Minute
+	identiconMaxAge      = time.Hour
+)
+
+// GetAvatar godoc
+// @Summary Get user avatar
+// @Description Redirect to the user's avatar, or render an identicon when the user has none.
+// @Description Identicons are derived from the user ID. Placeholder users (ID 0) are identified by the username parameter.
+// @Tags users
+// @Produce image/svg+xml
+// @Produce image/png
+// @Param id path int true "User ID, or 0 for a placeholder user"
+// @Param username query string false "Username of a placeholder user"
+// @Param format query string false "Identicon format" Enums(svg, png) default(svg)
+// @Param size query int false "Identicon PNG size in pixels (16-512)" default(128)
+// @Success 200 {file} file "Identicon"
+// @Success 302 "Redirect to the user's avatar"
+// @Success 304 "Not modified"
+// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /users/{id}/avatar [get]
+func (h *UserHandler) GetAvatar(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id < 0 {
+		h.log.Debug("Invalid user id for avatar", slog.String("id", idStr))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrInvalidInput.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = "svg"
+	}
+	if format != "svg" && format != "png" {
+		h.log.Debug("Invalid identicon format", slog.String("format", format))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+		return
+	}
+	size := defaultIdenticonSize
+	if s := query.Get("size"); s != "" {
+		size, err = strconv.Atoi(s)
+		if err != nil || size < minIdenticonSize || size > maxIdenticonSize {
+			h.log.Debug("Invalid identicon size", slog.String("size", s))
+			utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+			return
+		}
+	}
+
+	var icon *avatar.Identicon
+	seed := "id:" + strconv.FormatInt(id, 10)
+	if id == 0 {
+		username := query.Get("username")
+		if username == "" {
+			username = utils.GenerateUnknownAuthor().Username
+		}
+		icon, seed = avatar.ForUsername(username), "username:"+strings.ToLower(username)
+	} else {
+		user, err := h.userClient.GetUser(r.Context(), id)
+		if err != nil {
+			switch {
+			case errors.Is(err, custom_errors.ErrUserNotFound):
+				utils.SendError(w, http.StatusNotFound, custom_errors.ErrUserNotFound.Error())
+			default:
+				h.log.Error("Failed to get user for avatar", slog.Int64("id", id), slog.String("error", err.Error()))
+				utils.SendError(w, http.StatusInternalServerError, custom_errors.ErrExternalServiceError.Error())
+			}
+			return
+		}
+		if user.AvatarURL != nil && *user.AvatarURL != "" {
+			w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(avatarRedirectMaxAge.Seconds())))
+			http.Redirect(w, r, h.mediaURLs.Public(*user.AvatarURL), http.StatusFound)
+			return
+		}
+		icon = avatar.ForUserID(id)
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(identiconMaxAge.Seconds())))
+	if utils.CheckNotModified(w, r, utils.ResourceETag("identicon", seed, format, strconv.Itoa(size)), time.Time{}) {
+		return
+	}
+
+	body := icon.SVG()
+	contentType := "image/svg+xml"
+	if format == "png" {
+		body, err = icon.PNG(size)
+		if err != nil {
+			h.log.Error("Failed to render identicon", slog.String("error", err.Error()))
+			utils.SendError(w, http.StatusInternalServerError, "failed to render avatar")
+			return
+		}
+		contentType = "image/png"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
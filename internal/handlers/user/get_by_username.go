@@ -3,33 +3,32 @@ package user_handler
 import (
 	"errors"
 	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"log/slog"
 	"net/http"
+	"pinstack-api-gateway/internal/selection"
 	"pinstack-api-gateway/internal/utils"
 
 	"github.com/go-chi/chi/v5"
 )
 
-type GetUserByUsernameResponse struct {
-	ID        int64   `json:"id"`
-	Username  string  `json:"username"`
-	Email     string  `json:"email"`
-	FullName  *string `json:"full_name,omitempty"`
-	Bio       *string `json:"bio,omitempty"`
-	AvatarURL *string `json:"avatar_url,omitempty"`
-	CreatedAt string  `json:"created_at"`
-	UpdatedAt string  `json:"updated_at"`
-}
-
 // GetUserByUsername godoc
 // @Summary Get user by username
 // @Description Get user information by username
+// @Description The email is only included for the account owner and administrators.
+// @Description Authentication is optional; a bearer token that is sent must be valid.
+// @Description With Accept: application/x-protobuf the user is the user.v1.User message, which has no avatar_variants.
+// @Description It cannot be combined with fields (406).
 // @Tags users
 // @Produce json
+// @Produce application/msgpack
+// @Produce application/x-protobuf
 // @Param username path string true "User username"
-// @Success 200 {object} GetUserByUsernameResponse "User information"
+// @Param fields query string false "Comma separated fields to return, e.g. username,avatar_url"
+// @Success 200 {object} userview.User "User information"
 // @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 401 {object} map[string]string "Invalid bearer token"
 // @Failure 404 {object} map[string]string "User not found"
+// @Failure 406 {object} map[string]string "Requested media type cannot represent the response"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /users/username/{username} [get]
 func (h *UserHandler) GetUserByUsername(w http.ResponseWriter, r *http.Request) {
@@ -39,6 +38,13 @@ func (h *UserHandler) GetUserByUsername(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	sel, err := selection.Parse(r.URL.Query(), nil, nil)
+	if err != nil {
+		h.log.Debug("Invalid fields parameter", slog.String("query", r.URL.RawQuery))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+		return
+	}
+
 	user, err := h.userClient.GetUserByUsername(r.Context(), username)
 	if err != nil {
 		switch {
@@ -50,16 +56,8 @@ func (h *UserHandler) GetUserByUsername(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	response := GetUserByUsernameResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		FullName:  user.FullName,
-		Bio:       user.Bio,
-		AvatarURL: user.AvatarURL,
-		CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-	}
+	varyByCaller(w)
+	response := h.userResponse(r, user)
 
-	utils.Send(w, http.StatusOK, response)
+	utils.SendSelected(w, http.StatusOK, sel, response)
 }
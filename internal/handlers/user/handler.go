@@ -1,18 +1,47 @@
 package user_handler
 
 import (
+	"net/http"
 	user_client "pinstack-api-gateway/internal/clients/user"
 	"pinstack-api-gateway/internal/logger"
+	"pinstack-api-gateway/internal/media"
+	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/userview"
 )
 
 type UserHandler struct {
 	userClient user_client.UserClient
+	mediaURLs  *media.URLResolver
+	views      *userview.Policy
 	log        *logger.Logger
 }
 
-func NewUserHandler(userClient user_client.UserClient, log *logger.Logger) *UserHandler {
+func NewUserHandler(userClient user_client.UserClient, mediaURLs *media.URLResolver, views *userview.Policy, log *logger.Logger) *UserHandler {
 	return &UserHandler{
 		userClient: userClient,
+		mediaURLs:  mediaURLs,
+		views:      views,
 		log:        log,
 	}
 }
+
+// userResponse serialises user in the view the caller may see.
+func (h *UserHandler) userResponse(r *http.Request, user *models.User) userview.User {
+	resp := userview.NewUser(user, h.views.For(r.Context(), user.ID))
+	resp.AvatarURL = h.mediaURLs.PublicPtr(user.AvatarURL)
+	resp.AvatarVariants = h.avatarVariants(user.AvatarURL)
+	return resp
+}
+
+// avatarVariants returns the sized variants of an avatar uploaded through the gateway.
+func (h *UserHandler) avatarVariants(avatarURL *string) *models.MediaVariants {
+	if avatarURL == nil {
+		return nil
+	}
+	return h.mediaURLs.Variants(*avatarURL)
+}
+
+// varyByCaller tells caches that the response depends on who is asking, since views differ by caller.
+func varyByCaller(w http.ResponseWriter) {
+	w.Header().Add("Vary", "Authorization")
+}
@@ -0,0 +1,15 @@
+package user_handler
+
+import (
+	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/user/v1"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func (r SearchUsersResponse) ToProto() proto.Message {
+	msg := &pb.SearchUsersResponse{Users: make([]*pb.User, 0, len(r.Users)), Total: r.Total}
+	for _, u := range r.Users {
+		msg.Users = append(msg.Users, u.ToProto().(*pb.User))
+	}
+	return msg
+}
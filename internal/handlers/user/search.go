@@ -3,38 +3,40 @@ package user_handler
 import (
 	"errors"
 	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"log/slog"
 	"net/http"
+	"pinstack-api-gateway/internal/pagination"
+	"pinstack-api-gateway/internal/selection"
+	"pinstack-api-gateway/internal/userview"
 	"pinstack-api-gateway/internal/utils"
-	"strconv"
 )
 
 type SearchUsersResponse struct {
-	Users []UserResponse `json:"users"`
-	Total int64          `json:"total"`
-}
-
-type UserResponse struct {
-	ID        int64   `json:"id"`
-	Username  string  `json:"username"`
-	Email     string  `json:"email"`
-	FullName  *string `json:"full_name,omitempty"`
-	Bio       *string `json:"bio,omitempty"`
-	AvatarURL *string `json:"avatar_url,omitempty"`
-	CreatedAt string  `json:"created_at"`
-	UpdatedAt string  `json:"updated_at"`
+	Users []userview.User `json:"users"`
+	pagination.Meta
 }
 
 // SearchUsers godoc
 // @Summary Search users
 // @Description Search users by query string
+// @Description The email is only included for the account owner and administrators.
+// @Description Authentication is optional; a bearer token that is sent must be valid.
+// @Description With Accept: application/x-protobuf the page is the user.v1.SearchUsersResponse message: users and total only,
+// @Description without the other pagination meta or avatar_variants. It cannot be combined with fields (406).
 // @Tags users
 // @Produce json
+// @Produce application/msgpack
+// @Produce application/x-protobuf
 // @Param query query string true "Search query"
-// @Param page query int false "Page number"
-// @Param limit query int false "Results per page (max 100)"
+// @Param page query int false "Page number, starting at 1" default(1)
+// @Param offset query int false "Pagination offset, a multiple of limit; cannot be combined with page"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param fields query string false "Comma separated fields to return for each user, e.g. username,avatar_url"
 // @Success 200 {object} SearchUsersResponse "Search results"
+// @Header 200 {string} Link "RFC 8288 pagination links"
 // @Failure 400 {object} map[string]string "Bad request"
-// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 401 {object} map[string]string "Invalid bearer token"
+// @Failure 406 {object} map[string]string "Requested media type cannot represent the response"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /users/search [get]
 func (h *UserHandler) SearchUsers(w http.ResponseWriter, r *http.Request) {
@@ -44,19 +46,23 @@ func (h *UserHandler) SearchUsers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	page, err := strconv.Atoi(r.URL.Query().Get("page"))
-	if err != nil || page < 1 {
-		page = 1
+	params, err := pagination.Parse(r.URL.Query())
+	if err != nil || !params.PageAligned() {
+		h.log.Debug("Invalid pagination parameters", slog.String("query", r.URL.RawQuery))
+		utils.SendError(w, http.StatusBadRequest, pagination.ErrInvalidPagination.Error())
+		return
 	}
 
-	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
-	if err != nil || limit < 1 || limit > 100 {
-		limit = 10
+	sel, err := selection.Parse(r.URL.Query(), nil, nil)
+	if err != nil {
+		h.log.Debug("Invalid fields parameter", slog.String("query", r.URL.RawQuery))
+		utils.SendError(w, http.StatusBadRequest, custom_errors.ErrValidationFailed.Error())
+		return
 	}
 
-	h.log.Debug("Searching users", "query", query, "page", page, "limit", limit)
+	h.log.Debug("Searching users", "query", query, "page", params.Page, "limit", params.Limit)
 
-	users, total, err := h.userClient.SearchUsers(r.Context(), query, page, limit)
+	users, total, err := h.userClient.SearchUsers(r.Context(), query, params.Page, params.Limit)
 	if err != nil {
 		switch {
 		case errors.Is(err, custom_errors.ErrInvalidSearchQuery):
@@ -70,22 +76,15 @@ func (h *UserHandler) SearchUsers(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := SearchUsersResponse{
-		Users: make([]UserResponse, 0, len(users)),
-		Total: total,
+		Users: make([]userview.User, 0, len(users)),
+		Meta:  params.Meta(total, len(users)),
 	}
 
 	for _, user := range users {
-		response.Users = append(response.Users, UserResponse{
-			ID:        user.ID,
-			Username:  user.Username,
-			Email:     user.Email,
-			FullName:  user.FullName,
-			Bio:       user.Bio,
-			AvatarURL: user.AvatarURL,
-			CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		})
+		response.Users = append(response.Users, h.userResponse(r, user))
 	}
 
-	utils.Send(w, http.StatusOK, response)
+	varyByCaller(w)
+	params.SetLinkHeader(w, r, response.Meta)
+	utils.SendSelected(w, http.StatusOK, sel, response, "users")
 }
@@ -20,31 +20,28 @@ type UpdateUserRequest struct {
 	Bio      *string `json:"bio,omitempty" validate:"omitempty,max=500"`
 }
 
-type UpdateUserResponse struct {
-	ID        int64   `json:"id"`
-	Username  string  `json:"username"`
-	Email     string  `json:"email"`
-	FullName  *string `json:"full_name,omitempty"`
-	Bio       *string `json:"bio,omitempty"`
-	AvatarURL *string `json:"avatar_url,omitempty"`
-	CreatedAt string  `json:"created_at"`
-	UpdatedAt string  `json:"updated_at"`
-}
-
 // UpdateUser godoc
 // @Summary Update user information
 // @Description Update user fields by ID
+// @Description If-Match is checked against the current version before the update is sent. The user service has no
+// @Description conditional update, so a concurrent write landing between the check and the update is not detected.
+// @Description With Accept: application/x-protobuf the user is the user.v1.User message, which has no avatar_variants.
 // @Tags users
 // @Accept json
 // @Produce json
+// @Produce application/msgpack
+// @Produce application/x-protobuf
 // @Security BearerAuth
+// @Param If-Match header string false "ETag of the version being edited"
 // @Param request body UpdateUserRequest true "User update data"
-// @Success 200 {object} UpdateUserResponse "User updated successfully"
+// @Success 200 {object} userview.User "User updated successfully"
 // @Failure 400 {object} map[string]string "Bad request"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 403 {object} map[string]string "Operation not allowed"
 // @Failure 404 {object} map[string]string "User not found"
+// @Failure 406 {object} map[string]string "Requested media type cannot represent the response"
 // @Failure 409 {object} map[string]string "Username or email already exists"
+// @Failure 412 {object} map[string]string "User was modified concurrently"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /users [put]
 func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
@@ -79,6 +76,12 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if utils.PreconditionFailed(r, userETag(currentUser)) {
+		h.log.Debug("user version mismatch", slog.Int64("id", req.ID), slog.String("if_match", r.Header.Get("If-Match")))
+		utils.SendError(w, http.StatusPreconditionFailed, "precondition failed")
+		return
+	}
+
 	updateUser := &models.User{
 		ID:       req.ID,
 		Username: currentUser.Username,
@@ -117,16 +120,9 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := UpdateUserResponse{
-		ID:        updatedUser.ID,
-		Username:  updatedUser.Username,
-		Email:     updatedUser.Email,
-		FullName:  updatedUser.FullName,
-		Bio:       updatedUser.Bio,
-		AvatarURL: updatedUser.AvatarURL,
-		CreatedAt: updatedUser.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: updatedUser.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-	}
+	utils.SetValidators(w, userETag(updatedUser, utils.CodecVariant(w)), updatedUser.UpdatedAt)
+
+	response := h.userResponse(r, updatedUser)
 
 	utils.Send(w, http.StatusOK, response)
 }
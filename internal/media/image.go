@@ -0,0 +1,214 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+
+	_ "image/gif"
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+var (
+	ErrInvalidImage  = errors.New("invalid image")
+	ErrImageTooLarge = errors.New("image dimensions exceed limit")
+)
+
+// Variant names a size generated for an uploaded image.
+type Variant string
+
+const (
+	VariantThumbnail Variant = "thumb"
+	VariantMedium    Variant = "medium"
+	VariantFull      Variant = "full"
+)
+
+type ImageOptions struct {
+	// MaxPixels rejects images whose declared dimensions would decode to more pixels, before decoding them.
+	MaxPixels     int64
+	ThumbnailSize int
+	MediumSize    int
+	FullSize      int
+	JPEGQuality   int
+}
+
+func (o ImageOptions) withDefaults() ImageOptions {
+	if o.MaxPixels <= 0 {
+		o.MaxPixels = 40_000_000
+	}
+	if o.ThumbnailSize <= 0 {
+		o.ThumbnailSize = 320
+	}
+	if o.MediumSize <= 0 {
+		o.MediumSize = 1080
+	}
+	if o.FullSize <= 0 {
+		o.FullSize = 2048
+	}
+	if o.JPEGQuality <= 0 || o.JPEGQuality > 100 {
+		o.JPEGQuality = 85
+	}
+	return o
+}
+
+type EncodedImage struct {
+	Data   []byte
+	Width  int
+	Height int
+}
+
+// ProcessedImage is an uploaded image re-encoded without metadata at every variant size.
+type ProcessedImage struct {
+	ContentType string
+	Variants    map[Variant]EncodedImage
+}
+
+// ProcessImage decodes an uploaded image of the sniffed contentType, applies its EXIF orientation
+// and re-encodes it at the thumbnail, medium and full sizes. Re-encoding drops EXIF and any other
+// metadata. JPEGs and opaque WebPs become JPEGs; everything else, including the first frame of an
+// animated GIF, becomes a PNG.
+func ProcessImage(r io.ReadSeeker, contentType string, opts ImageOptions) (*ProcessedImage, error) {
+	opts = opts.withDefaults()
+
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, ErrInvalidImage
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > opts.MaxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	orientation := 1
+	if contentType == "image/jpeg" {
+		if _, err := r.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		orientation = jpegOrientation(r)
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	src, _, err := image.Decode(r)
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	asJPEG := contentType == "image/jpeg" || (contentType == "image/webp" && isOpaque(src))
+	encode := func(img *image.RGBA) (EncodedImage, error) {
+		var buf bytes.Buffer
+		var err error
+		if asJPEG {
+			err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: opts.JPEGQuality})
+		} else {
+			err = png.Encode(&buf, img)
+		}
+		return EncodedImage{Data: buf.Bytes(), Width: img.Bounds().Dx(), Height: img.Bounds().Dy()}, err
+	}
+
+	full := orient(fit(src, opts.FullSize), orientation)
+	sized := map[Variant]*image.RGBA{
+		VariantFull:      full,
+		VariantMedium:    fit(full, opts.MediumSize),
+		VariantThumbnail: fit(full, opts.ThumbnailSize),
+	}
+
+	processed := &ProcessedImage{
+		ContentType: "image/png",
+		Variants:    make(map[Variant]EncodedImage, len(sized)),
+	}
+	if asJPEG {
+		processed.ContentType = "image/jpeg"
+	}
+	for variant, img := range sized {
+		encoded, err := encode(img)
+		if err != nil {
+			return nil, err
+		}
+		processed.Variants[variant] = encoded
+	}
+	return processed, nil
+}
+
+// VariantKey derives the object key of a variant from the key of the upload.
+func VariantKey(key string, variant Variant) string {
+	dot := strings.LastIndex(key, ".")
+	if dot <= strings.LastIndex(key, "/") {
+		return key + "_" + string(variant)
+	}
+	return key[:dot] + "_" + string(variant) + key[dot:]
+}
+
+// fit scales src down to fit within size x size, keeping its aspect ratio. Smaller images are copied as they are.
+func fit(src image.Image, size int) *image.RGBA {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > size || h > size {
+		if w >= h {
+			w, h = size, max(1, h*size/w)
+		} else {
+			w, h = max(1, w*size/h), size
+		}
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	if w == b.Dx() && h == b.Dy() {
+		xdraw.Draw(dst, dst.Bounds(), src, b.Min, xdraw.Src)
+	} else {
+		xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, b, xdraw.Src, nil)
+	}
+	return dst
+}
+
+// orient transforms src so that it displays upright given its EXIF orientation.
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			si := src.PixOffset(x, y)
+			di := dst.PixOffset(dx, dy)
+			copy(dst.Pix[di:di+4], src.Pix[si:si+4])
+		}
+	}
+	return dst
+}
+
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return false
+}
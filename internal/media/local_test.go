@@ -0,0 +1,44 @@
+package media
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStorePath(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewLocalStore(root, "http://localhost/media")
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	tests := []struct {
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{key: "photo.jpg", want: "photo.jpg"},
+		{key: "posts/1/photo.jpg", want: "posts/1/photo.jpg"},
+		{key: "", wantErr: true},
+		{key: "/", wantErr: true},
+		{key: "/etc/passwd", wantErr: true},
+		{key: "../secret", wantErr: true},
+		{key: "posts/../../secret", wantErr: true},
+		{key: "posts/../photo.jpg", wantErr: true},
+		{key: "posts/./photo.jpg", wantErr: true},
+		{key: "posts//photo.jpg", wantErr: true},
+		{key: "posts/", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := store.path(tt.key)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("path(%q) = %q, %v; want %v", tt.key, got, err, ErrInvalidKey)
+			}
+			continue
+		}
+		if want := filepath.Join(root, filepath.FromSlash(tt.want)); err != nil || got != want {
+			t.Errorf("path(%q) = %q, %v; want %q", tt.key, got, err, want)
+		}
+	}
+}
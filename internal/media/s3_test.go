@@ -0,0 +1,246 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testAccessKey = "AKIDEXAMPLE"
+	testSecretKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	testRegion    = "eu-central-1"
+	testBucket    = "media"
+)
+
+type s3Stored struct {
+	body        []byte
+	contentType string
+}
+
+// fakeS3 is an in-memory, path-style S3 stand-in. It checks every request's SigV4 signature
+// independently of S3Store and serves PUT, GET (with open-ended ranges) and DELETE.
+type fakeS3 struct {
+	t *testing.T
+
+	mu      sync.Mutex
+	objects map[string]s3Stored
+	ranges  []string
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *S3Store) {
+	t.Helper()
+	fake := &fakeS3{t: t, objects: make(map[string]s3Stored)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	store, err := NewS3Store(S3Options{
+		Endpoint:  server.URL,
+		Region:    testRegion,
+		Bucket:    testBucket,
+		AccessKey: testAccessKey,
+		SecretKey: testSecretKey,
+		PathStyle: true,
+	}, server.Client())
+	if err != nil {
+		t.Fatalf("NewS3Store: %v", err)
+	}
+	return fake, store
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := verifySignature(r); err != nil {
+		f.t.Errorf("%s %s: %v", r.Method, r.URL.Path, err)
+		http.Error(w, "SignatureDoesNotMatch", http.StatusForbidden)
+		return
+	}
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+testBucket+"/")
+	if !ok {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = s3Stored{body: body, contentType: r.Header.Get("Content-Type")}
+	case http.MethodGet:
+		obj, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", obj.contentType)
+		w.Header().Set("Last-Modified", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC).Format(http.TimeFormat))
+		rng := r.Header.Get("Range")
+		f.ranges = append(f.ranges, rng)
+		if rng == "" {
+			w.Header().Set("Content-Length", strconv.Itoa(len(obj.body)))
+			_, _ = w.Write(obj.body)
+			return
+		}
+		start, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+		if err != nil || start >= len(obj.body) {
+			http.Error(w, "InvalidRange", http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		w.Header().Set("Content-Range", "bytes "+strconv.Itoa(start)+"-"+strconv.Itoa(len(obj.body)-1)+"/"+strconv.Itoa(len(obj.body)))
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = w.Write(obj.body[start:])
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// verifySignature recomputes the SigV4 signature of r from what arrived on the wire.
+func verifySignature(r *http.Request) error {
+	auth := r.Header.Get("Authorization")
+	rest, ok := strings.CutPrefix(auth, s3Algorithm+" ")
+	if !ok {
+		return errors.New("missing SigV4 authorization")
+	}
+	fields := make(map[string]string)
+	for _, part := range strings.Split(rest, ", ") {
+		name, value, _ := strings.Cut(part, "=")
+		fields[name] = value
+	}
+	amzDate := r.Header.Get("X-Amz-Date")
+	if len(amzDate) != len("20060102T150405Z") {
+		return errors.New("bad X-Amz-Date " + amzDate)
+	}
+	scope := amzDate[:8] + "/" + testRegion + "/s3/aws4_request"
+	if fields["Credential"] != testAccessKey+"/"+scope {
+		return errors.New("bad credential " + fields["Credential"])
+	}
+	if r.Header.Get("X-Amz-Content-Sha256") != unsignedPayload {
+		return errors.New("payload hash header missing")
+	}
+
+	signed := strings.Split(fields["SignedHeaders"], ";")
+	var headers strings.Builder
+	for _, name := range signed {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		headers.WriteString(name + ":" + value + "\n")
+	}
+	canonical := strings.Join([]string{
+		r.Method,
+		r.URL.EscapedPath(),
+		r.URL.RawQuery,
+		headers.String(),
+		fields["SignedHeaders"],
+		unsignedPayload,
+	}, "\n")
+	stringToSign := strings.Join([]string{s3Algorithm, amzDate, scope, hexSHA256(canonical)}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+testSecretKey), amzDate[:8])
+	for _, part := range []string{testRegion, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	if want := hex.EncodeToString(hmacSHA256(key, stringToSign)); fields["Signature"] != want {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+func TestS3StoreRoundTrip(t *testing.T) {
+	fake, store := newFakeS3(t)
+	ctx := context.Background()
+	const key = "posts/1/photo one+.jpg"
+	data := []byte("0123456789abcdef")
+
+	if err := store.Put(ctx, key, bytes.NewReader(data), int64(len(data)), "image/jpeg"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if got := fake.objects[key]; !bytes.Equal(got.body, data) || got.contentType != "image/jpeg" {
+		t.Fatalf("stored %q (%s), want %q (image/jpeg)", got.body, got.contentType, data)
+	}
+
+	body, info, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer body.Close()
+	if info.Size != int64(len(data)) || info.ContentType != "image/jpeg" || info.LastModified.IsZero() {
+		t.Errorf("got info %+v", info)
+	}
+	got, err := io.ReadAll(body)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Errorf("read %q, %v; want %q", got, err, data)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok := fake.objects[key]; ok {
+		t.Error("object still stored after Delete")
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Errorf("Delete of a missing object: %v", err)
+	}
+}
+
+func TestS3StoreRangedRead(t *testing.T) {
+	fake, store := newFakeS3(t)
+	ctx := context.Background()
+	data := []byte("0123456789abcdef")
+	if err := store.Put(ctx, "clip.mp4", bytes.NewReader(data), int64(len(data)), "video/mp4"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	body, _, err := store.Get(ctx, "clip.mp4")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer body.Close()
+	seeker, ok := body.(io.ReadSeeker)
+	if !ok {
+		t.Fatal("S3 object is not seekable")
+	}
+	if _, err := seeker.Seek(10, io.SeekStart); err != nil {
+		t.Fatalf("Seek: %v", err)
+	}
+	got, err := io.ReadAll(seeker)
+	if err != nil || string(got) != "abcdef" {
+		t.Errorf("read %q, %v; want %q", got, err, "abcdef")
+	}
+	if want := []string{"", "bytes=10-"}; strings.Join(fake.ranges, ",") != strings.Join(want, ",") {
+		t.Errorf("got Range headers %q, want %q", fake.ranges, want)
+	}
+}
+
+func TestS3StoreGetMissing(t *testing.T) {
+	_, store := newFakeS3(t)
+	if _, _, err := store.Get(context.Background(), "missing.jpg"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("got error %v, want %v", err, ErrObjectNotFound)
+	}
+}
+
+func TestS3StoreRejectsInvalidKeys(t *testing.T) {
+	_, store := newFakeS3(t)
+	for _, key := range []string{"", "/absolute.jpg"} {
+		if err := store.Put(context.Background(), key, strings.NewReader("x"), 1, ""); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Put(%q): got error %v, want %v", key, err, ErrInvalidKey)
+		}
+	}
+}
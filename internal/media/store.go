@@ -0,0 +1,27 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+var ErrObjectNotFound = errors.New("media object not found")
+
+// ObjectInfo describes a stored media object.
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
+// MediaStore persists uploaded media. Keys are slash separated relative paths chosen by the gateway.
+type MediaStore interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error)
+	Delete(ctx context.Context, key string) error
+	// URL returns the public URL clients use to reference the object, e.g. in media_items or as an avatar.
+	URL(key string) string
+}
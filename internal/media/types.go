@@ -0,0 +1,103 @@
+package media
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"pinstack-api-gateway/internal/models"
+	"strconv"
+	"strings"
+)
+
+// sniffLen is the number of leading bytes http.DetectContentType considers.
+const sniffLen = 512
+
+var (
+	ErrUnsupportedType = errors.New("unsupported media type")
+	ErrTypeMismatch    = errors.New("file content does not match declared media type")
+	ErrInvalidKey      = errors.New("invalid media key")
+)
+
+type format struct {
+	mediaType models.MediaType
+	ext       string
+}
+
+// formats lists the sniffed content types accepted for upload.
+var formats = map[string]format{
+	"image/jpeg": {models.MediaTypeImage, ".jpg"},
+	"image/png":  {models.MediaTypeImage, ".png"},
+	"image/gif":  {models.MediaTypeImage, ".gif"},
+	"image/webp": {models.MediaTypeImage, ".webp"},
+	"video/mp4":  {models.MediaTypeVideo, ".mp4"},
+	"video/webm": {models.MediaTypeVideo, ".webm"},
+}
+
+// ContentTypeByURL guesses the content type of a media URL from its extension.
+// It returns an empty string when the extension is unknown.
+func ContentTypeByURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	ext := strings.ToLower(path.Ext(u.Path))
+	if ext == ".jpeg" {
+		ext = ".jpg"
+	}
+	for contentType, f := range formats {
+		if f.ext == ext {
+			return contentType
+		}
+	}
+	contentType, _, _ := strings.Cut(mime.TypeByExtension(ext), ";")
+	return contentType
+}
+
+// Limits caps the size in bytes of an upload per media type.
+type Limits struct {
+	Image int64
+	Video int64
+}
+
+func (l Limits) For(mediaType models.MediaType) int64 {
+	switch mediaType {
+	case models.MediaTypeImage:
+		return l.Image
+	case models.MediaTypeVideo:
+		return l.Video
+	default:
+		return 0
+	}
+}
+
+// Max is the largest limit over all media types.
+func (l Limits) Max() int64 {
+	return max(l.Image, l.Video)
+}
+
+// Sniff detects the content type of a file from its leading bytes.
+// Only formats the gateway accepts are recognised; anything else is ErrUnsupportedType.
+func Sniff(head []byte) (contentType string, mediaType models.MediaType, err error) {
+	if len(head) > sniffLen {
+		head = head[:sniffLen]
+	}
+	contentType = http.DetectContentType(head)
+	f, ok := formats[contentType]
+	if !ok {
+		return contentType, "", ErrUnsupportedType
+	}
+	return contentType, f.mediaType, nil
+}
+
+// NewKey returns a fresh, unguessable object key for a file uploaded by userID.
+func NewKey(userID int64, contentType string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(userID, 10) + "/" + hex.EncodeToString(buf) + formats[contentType].ext, nil
+}
@@ -0,0 +1,253 @@
+package media
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"pinstack-api-gateway/internal/models"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUploadNotFound = errors.New("upload session not found")
+	ErrOffsetMismatch = errors.New("chunk does not start at the current upload offset")
+	ErrChunkOverflow  = errors.New("chunk extends past the declared upload size")
+	ErrUploadBusy     = errors.New("upload session is receiving another chunk")
+	ErrTooManyUploads = errors.New("too many open upload sessions")
+)
+
+// Upload is a resumable upload session. Chunks are appended to a spool file
+// until Size bytes have been received.
+type Upload struct {
+	ID        string
+	UserID    int64
+	Type      models.MediaType
+	Size      int64
+	Offset    int64
+	ExpiresAt time.Time
+
+	path string
+	busy bool
+}
+
+// Complete reports whether every byte of the upload has been received.
+func (u *Upload) Complete() bool {
+	return u.Offset == u.Size
+}
+
+// Uploads tracks resumable upload sessions, spooling their data under dir.
+// Sessions idle for longer than ttl are discarded, and a user may hold at most maxPerUser open sessions.
+type Uploads struct {
+	dir        string
+	ttl        time.Duration
+	maxPerUser int
+
+	mu       sync.Mutex
+	sessions map[string]*Upload
+}
+
+// spoolSuffix marks the spool files of upload sessions, which are named after the session ID.
+const spoolSuffix = ".upload"
+
+// NewUploads prepares dir for spooling. Sessions only live in memory, so spool files left in dir
+// by a previous run are orphaned and removed; other files in dir are left alone.
+// A maxPerUser of zero or less leaves the number of sessions per user unlimited.
+func NewUploads(dir string, ttl time.Duration, maxPerUser int) (*Uploads, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !isSpoolFile(entry.Name()) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return &Uploads{
+		dir:        dir,
+		ttl:        ttl,
+		maxPerUser: maxPerUser,
+		sessions:   make(map[string]*Upload),
+	}, nil
+}
+
+// Create opens a session for a file of size bytes. It fails with ErrTooManyUploads when the user
+// already holds the maximum number of open sessions.
+func (u *Uploads) Create(userID int64, mediaType models.MediaType, size int64) (Upload, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.sweep(time.Now())
+	if u.maxPerUser > 0 && u.openSessions(userID) >= u.maxPerUser {
+		return Upload{}, ErrTooManyUploads
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return Upload{}, err
+	}
+	id := hex.EncodeToString(buf)
+	path := filepath.Join(u.dir, id+spoolSuffix)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return Upload{}, err
+	}
+	if err := f.Close(); err != nil {
+		return Upload{}, err
+	}
+
+	session := &Upload{
+		ID:        id,
+		UserID:    userID,
+		Type:      mediaType,
+		Size:      size,
+		ExpiresAt: time.Now().Add(u.ttl),
+		path:      path,
+	}
+	u.sessions[id] = session
+	return *session, nil
+}
+
+// Get returns the session id owned by userID.
+func (u *Uploads) Get(userID int64, id string) (Upload, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	session, err := u.lookup(userID, id)
+	if err != nil {
+		return Upload{}, err
+	}
+	return *session, nil
+}
+
+// Append writes a chunk starting at offset. The chunk is rejected unless offset matches
+// the bytes already received, so a client resumes by asking for the session's offset.
+func (u *Uploads) Append(userID int64, id string, offset int64, chunk io.Reader, length int64) (Upload, error) {
+	u.mu.Lock()
+	session, err := u.lookup(userID, id)
+	if err == nil && session.busy {
+		err = ErrUploadBusy
+	}
+	if err == nil && offset != session.Offset {
+		err = ErrOffsetMismatch
+	}
+	if err == nil && offset+length > session.Size {
+		err = ErrChunkOverflow
+	}
+	if err != nil {
+		u.mu.Unlock()
+		return Upload{}, err
+	}
+	session.busy = true
+	u.mu.Unlock()
+
+	written, err := appendFile(session.path, offset, chunk, length)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	session.busy = false
+	session.Offset = offset + written
+	session.ExpiresAt = time.Now().Add(u.ttl)
+	return *session, err
+}
+
+// Open returns the spooled data of a complete upload.
+func (u *Uploads) Open(userID int64, id string) (*os.File, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	session, err := u.lookup(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(session.path)
+}
+
+// Remove discards the session and its spooled data.
+func (u *Uploads) Remove(userID int64, id string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	session, err := u.lookup(userID, id)
+	if err != nil {
+		return err
+	}
+	if session.busy {
+		return ErrUploadBusy
+	}
+	u.discard(session)
+	return nil
+}
+
+func (u *Uploads) lookup(userID int64, id string) (*Upload, error) {
+	session, ok := u.sessions[id]
+	if !ok || session.UserID != userID {
+		return nil, ErrUploadNotFound
+	}
+	if time.Now().After(session.ExpiresAt) && !session.busy {
+		u.discard(session)
+		return nil, ErrUploadNotFound
+	}
+	return session, nil
+}
+
+func (u *Uploads) openSessions(userID int64) int {
+	open := 0
+	for _, session := range u.sessions {
+		if session.UserID == userID {
+			open++
+		}
+	}
+	return open
+}
+
+func (u *Uploads) sweep(now time.Time) {
+	for _, session := range u.sessions {
+		if now.After(session.ExpiresAt) && !session.busy {
+			u.discard(session)
+		}
+	}
+}
+
+func (u *Uploads) discard(session *Upload) {
+	delete(u.sessions, session.ID)
+	_ = os.Remove(session.path)
+}
+
+// appendFile writes at most length bytes from r at offset and truncates anything a failed
+// earlier attempt left behind it, returning how many bytes were kept.
+func appendFile(path string, offset int64, r io.Reader, length int64) (int64, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	if err := f.Truncate(offset); err != nil {
+		return 0, err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	written, err := io.Copy(f, io.LimitReader(r, length))
+	if err == nil && written != length {
+		err = io.ErrUnexpectedEOF
+	}
+	return written, err
+}
+
+// isSpoolFile reports whether name is a spool file as created by Create: a session ID and spoolSuffix.
+func isSpoolFile(name string) bool {
+	id, ok := strings.CutSuffix(name, spoolSuffix)
+	if !ok || len(id) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
@@ -0,0 +1,146 @@
+package media
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"pinstack-api-gateway/internal/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUploadsAppend(t *testing.T) {
+	const userID = 7
+	tests := []struct {
+		name       string
+		received   string
+		busy       bool
+		userID     int64
+		offset     int64
+		chunk      string
+		wantErr    error
+		wantOffset int64
+	}{
+		{name: "first chunk", chunk: "hello", wantOffset: 5},
+		{name: "next chunk", received: "hello", offset: 5, chunk: "world", wantOffset: 10},
+		{name: "offset behind", received: "hello", offset: 3, chunk: "world", wantErr: ErrOffsetMismatch},
+		{name: "offset ahead", received: "hello", offset: 6, chunk: "orld", wantErr: ErrOffsetMismatch},
+		{name: "overflow", received: "hello", offset: 5, chunk: "world!", wantErr: ErrChunkOverflow},
+		{name: "busy", received: "hello", busy: true, offset: 5, chunk: "world", wantErr: ErrUploadBusy},
+		{name: "other user", userID: userID + 1, chunk: "hello", wantErr: ErrUploadNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uploads, err := NewUploads(t.TempDir(), time.Hour, 0)
+			if err != nil {
+				t.Fatalf("NewUploads: %v", err)
+			}
+			session, err := uploads.Create(userID, models.MediaTypeImage, 10)
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			if tt.received != "" {
+				if _, err := uploads.Append(userID, session.ID, 0, strings.NewReader(tt.received), int64(len(tt.received))); err != nil {
+					t.Fatalf("Append: %v", err)
+				}
+			}
+			uploads.sessions[session.ID].busy = tt.busy
+			caller := tt.userID
+			if caller == 0 {
+				caller = userID
+			}
+
+			got, err := uploads.Append(caller, session.ID, tt.offset, strings.NewReader(tt.chunk), int64(len(tt.chunk)))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if stored := uploads.sessions[session.ID].Offset; stored != int64(len(tt.received)) {
+					t.Errorf("rejected chunk moved the offset to %d", stored)
+				}
+				return
+			}
+			if got.Offset != tt.wantOffset {
+				t.Errorf("got offset %d, want %d", got.Offset, tt.wantOffset)
+			}
+			data, err := os.ReadFile(uploads.sessions[session.ID].path)
+			if err != nil || string(data) != tt.received+tt.chunk {
+				t.Errorf("spooled %q, %v; want %q", data, err, tt.received+tt.chunk)
+			}
+		})
+	}
+}
+
+func TestUploadsAppendShortChunk(t *testing.T) {
+	uploads, err := NewUploads(t.TempDir(), time.Hour, 0)
+	if err != nil {
+		t.Fatalf("NewUploads: %v", err)
+	}
+	session, err := uploads.Create(1, models.MediaTypeImage, 10)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	got, err := uploads.Append(1, session.ID, 0, strings.NewReader("abc"), 5)
+	if err == nil {
+		t.Fatal("want an error for a chunk shorter than its declared length")
+	}
+	if got.Offset != 3 {
+		t.Errorf("got offset %d, want the 3 bytes received", got.Offset)
+	}
+	if _, err := uploads.Append(1, session.ID, 3, strings.NewReader("defgh"), 5); err != nil {
+		t.Errorf("resuming at the reported offset: %v", err)
+	}
+}
+
+func TestUploadsCreateLimitsOpenSessions(t *testing.T) {
+	uploads, err := NewUploads(t.TempDir(), time.Hour, 2)
+	if err != nil {
+		t.Fatalf("NewUploads: %v", err)
+	}
+	first, err := uploads.Create(1, models.MediaTypeImage, 10)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := uploads.Create(1, models.MediaTypeImage, 10); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := uploads.Create(1, models.MediaTypeImage, 10); !errors.Is(err, ErrTooManyUploads) {
+		t.Fatalf("got error %v, want %v", err, ErrTooManyUploads)
+	}
+	if _, err := uploads.Create(2, models.MediaTypeImage, 10); err != nil {
+		t.Errorf("another user: %v", err)
+	}
+	if err := uploads.Remove(1, first.ID); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if _, err := uploads.Create(1, models.MediaTypeImage, 10); err != nil {
+		t.Errorf("after removing a session: %v", err)
+	}
+}
+
+func TestNewUploadsClearsOrphanedSpoolFiles(t *testing.T) {
+	dir := t.TempDir()
+	orphan := "0123456789abcdef0123456789abcdef" + spoolSuffix
+	keep := []string{"0123abcd", "0123456789abcdef0123456789abcdef.jpg", "notes" + spoolSuffix}
+	for _, name := range append([]string{orphan}, keep...) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("data"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "media"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewUploads(dir, time.Hour, 0); err != nil {
+		t.Fatalf("NewUploads: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, orphan)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("orphaned spool file still present (%v)", err)
+	}
+	for _, name := range append(keep, "media") {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s: %v, want it left alone", name, err)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package media
+
+import (
+	"net/url"
+	"pinstack-api-gateway/internal/models"
+	"strings"
+	"time"
+)
+
+// URLResolver recognises URLs of media stored through the gateway and derives the URLs
+// handed to clients from them. Stored URLs stay plain; with a signer configured they are
+// rewritten into signed, expiring URLs served by the gateway under signedBase.
+type URLResolver struct {
+	store      MediaStore
+	base       string
+	signer     *URLSigner
+	signedBase string
+}
+
+func NewURLResolver(store MediaStore, signer *URLSigner, signedBase string) *URLResolver {
+	return &URLResolver{
+		store:      store,
+		base:       store.URL(""),
+		signer:     signer,
+		signedBase: strings.TrimRight(signedBase, "/"),
+	}
+}
+
+// Signed reports whether media URLs are signed.
+func (u *URLResolver) Signed() bool {
+	return u.signer != nil
+}
+
+// Window returns the start of the current signing window, or the zero time when URLs are not signed.
+// Responses embedding media URLs change when it does.
+func (u *URLResolver) Window() time.Time {
+	if u.signer == nil {
+		return time.Time{}
+	}
+	return u.signer.Window()
+}
+
+// Public returns the URL clients should use for rawURL. URLs not stored through the gateway are returned as they are.
+func (u *URLResolver) Public(rawURL string) string {
+	key, ok := u.key(rawURL)
+	if !ok {
+		return rawURL
+	}
+	return u.keyURL(key)
+}
+
+// PublicPtr is Public for optional URLs.
+func (u *URLResolver) PublicPtr(rawURL *string) *string {
+	if rawURL == nil {
+		return nil
+	}
+	public := u.Public(*rawURL)
+	return &public
+}
+
+// Verify checks the signature query of a request for key. It always succeeds when URLs are not signed.
+func (u *URLResolver) Verify(key string, query url.Values) error {
+	if u.signer == nil {
+		return nil
+	}
+	return u.signer.Verify(key, query.Get("exp"), query.Get("sig"))
+}
+
+// Expires returns when the signed URL carrying query stops being valid, or the zero time when URLs are not signed.
+func (u *URLResolver) Expires(query url.Values) time.Time {
+	if u.signer == nil {
+		return time.Time{}
+	}
+	return u.signer.Expires(query.Get("exp"))
+}
+
+// Variants returns the variant URLs of an image uploaded through the gateway,
+// given the URL of any of its variants. Other URLs have no variants and yield nil.
+func (u *URLResolver) Variants(rawURL string) *models.MediaVariants {
+	key, ok := u.key(rawURL)
+	if !ok {
+		return nil
+	}
+	base, ok := variantBase(key)
+	if !ok {
+		return nil
+	}
+	return &models.MediaVariants{
+		Thumbnail: u.keyURL(VariantKey(base, VariantThumbnail)),
+		Medium:    u.keyURL(VariantKey(base, VariantMedium)),
+		Full:      u.keyURL(VariantKey(base, VariantFull)),
+	}
+}
+
+// key extracts the object key from a stored URL, ignoring any query such as a previous signature.
+func (u *URLResolver) key(rawURL string) (string, bool) {
+	key, ok := strings.CutPrefix(rawURL, u.base)
+	if !ok && u.signer != nil {
+		key, ok = strings.CutPrefix(rawURL, u.signedBase+"/")
+	}
+	if !ok {
+		return "", false
+	}
+	key, _, _ = strings.Cut(key, "?")
+	return key, key != ""
+}
+
+func (u *URLResolver) keyURL(key string) string {
+	if u.signer == nil {
+		return u.store.URL(key)
+	}
+	exp, sig := u.signer.Sign(key)
+	return u.signedBase + "/" + key + "?" + url.Values{"exp": {exp}, "sig": {sig}}.Encode()
+}
+
+// variantBase reverses VariantKey.
+func variantBase(key string) (string, bool) {
+	ext := ""
+	stem := key
+	if dot := strings.LastIndex(key, "."); dot > strings.LastIndex(key, "/") {
+		stem, ext = key[:dot], key[dot:]
+	}
+	for _, variant := range []Variant{VariantThumbnail, VariantMedium, VariantFull} {
+		if base, ok := strings.CutSuffix(stem, "_"+string(variant)); ok && base != "" {
+			return base + ext, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,294 @@
+package middlewares
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	encodingGzip = "gzip"
+	encodingZstd = "zstd"
+)
+
+// CompressionOptions configures CompressionMiddleware.
+type CompressionOptions struct {
+	MinSize      int
+	GzipLevel    int
+	ContentTypes []string
+}
+
+var zstdEncoderPool = sync.Pool{
+	New: func() interface{} {
+		enc, err := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1), zstd.WithEncoderLevel(zstd.SpeedDefault))
+		if err != nil {
+			return nil
+		}
+		return enc
+	},
+}
+
+// CompressionMiddleware compresses responses with gzip or zstd based on Accept-Encoding.
+// Responses are buffered until MinSize bytes are written, and only allowlisted content types are compressed.
+// It has to sit inside MetricsMiddleware so that the wrapped writer counts bytes sent on the wire.
+func CompressionMiddleware(opts CompressionOptions) func(next http.Handler) http.Handler {
+	allowed := make(map[string]struct{}, len(opts.ContentTypes))
+	for _, ct := range opts.ContentTypes {
+		allowed[strings.ToLower(strings.TrimSpace(ct))] = struct{}{}
+	}
+	level := opts.GzipLevel
+	if level == gzip.NoCompression || level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		level = gzip.DefaultCompression
+	}
+	gzipPool := &sync.Pool{
+		New: func() interface{} {
+			gz, _ := gzip.NewWriterLevel(nil, level)
+			return gz
+		},
+	}
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead || r.Header.Get("Range") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressResponseWriter{
+				ResponseWriter: w,
+				encoding:       encoding,
+				minSize:        opts.MinSize,
+				gzipPool:       gzipPool,
+				allowed:        allowed,
+				status:         http.StatusOK,
+			}
+			defer func() {
+				_ = cw.Close()
+			}()
+
+			next.ServeHTTP(cw, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// negotiateEncoding returns the preferred supported encoding, or "" when identity should be used.
+func negotiateEncoding(header string) string {
+	if header == "" {
+		return ""
+	}
+
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		coding := strings.ToLower(strings.TrimSpace(fields[0]))
+		q := 1.0
+		for _, param := range fields[1:] {
+			key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if found && strings.TrimSpace(key) == "q" {
+				if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+
+		switch coding {
+		case "*":
+			coding = encodingGzip
+		case encodingGzip, "x-gzip":
+			coding = encodingGzip
+		case encodingZstd:
+		default:
+			continue
+		}
+
+		// zstd wins ties because it is cheaper to produce at comparable ratios.
+		if q > bestQ || (q == bestQ && coding == encodingZstd) {
+			best, bestQ = coding, q
+		}
+	}
+	return best
+}
+
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+	gzipPool *sync.Pool
+	allowed  map[string]struct{}
+
+	status      int
+	wroteHeader bool
+	decided     bool
+	buf         []byte
+	encoder     io.WriteCloser
+	release     func()
+}
+
+func (cw *compressResponseWriter) WriteHeader(code int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	cw.status = code
+
+	if code < http.StatusOK || code == http.StatusNoContent || code == http.StatusNotModified {
+		cw.decide(false)
+	}
+}
+
+func (cw *compressResponseWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+
+	if cw.decided {
+		if cw.encoder != nil {
+			return cw.encoder.Write(p)
+		}
+		return cw.ResponseWriter.Write(p)
+	}
+
+	cw.buf = append(cw.buf, p...)
+	if len(cw.buf) >= cw.minSize {
+		if err := cw.flushBuffer(cw.shouldCompress()); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (cw *compressResponseWriter) shouldCompress() bool {
+	h := cw.Header()
+	if h.Get("Content-Encoding") != "" || h.Get("Content-Range") != "" {
+		return false
+	}
+	if cw.status == http.StatusPartialContent {
+		return false
+	}
+
+	ct := h.Get("Content-Type")
+	if ct == "" {
+		ct = http.DetectContentType(cw.buf)
+	}
+	mediaType, _, _ := strings.Cut(ct, ";")
+	_, ok := cw.allowed[strings.ToLower(strings.TrimSpace(mediaType))]
+	return ok
+}
+
+// decide fixes the encoding for the rest of the response and sends the status line.
+func (cw *compressResponseWriter) decide(compress bool) {
+	if cw.decided {
+		return
+	}
+	cw.decided = true
+
+	if compress {
+		h := cw.Header()
+		h.Set("Content-Encoding", cw.encoding)
+		h.Del("Content-Length")
+		if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			h.Set("ETag", "W/"+etag)
+		}
+		cw.encoder, cw.release = cw.newEncoder()
+	}
+
+	cw.ResponseWriter.WriteHeader(cw.status)
+}
+
+func (cw *compressResponseWriter) flushBuffer(compress bool) error {
+	cw.decide(compress)
+
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if cw.encoder != nil {
+		_, err := cw.encoder.Write(buf)
+		return err
+	}
+	_, err := cw.ResponseWriter.Write(buf)
+	return err
+}
+
+func (cw *compressResponseWriter) newEncoder() (io.WriteCloser, func()) {
+	switch cw.encoding {
+	case encodingZstd:
+		if enc, ok := zstdEncoderPool.Get().(*zstd.Encoder); ok && enc != nil {
+			enc.Reset(cw.ResponseWriter)
+			return enc, func() { zstdEncoderPool.Put(enc) }
+		}
+	case encodingGzip:
+		if gz, ok := cw.gzipPool.Get().(*gzip.Writer); ok && gz != nil {
+			gz.Reset(cw.ResponseWriter)
+			return gz, func() { cw.gzipPool.Put(gz) }
+		}
+	}
+
+	cw.Header().Del("Content-Encoding")
+	return nil, nil
+}
+
+// Close flushes any buffered bytes and finalises the compressed stream.
+func (cw *compressResponseWriter) Close() error {
+	if !cw.decided {
+		if !cw.wroteHeader {
+			// Nothing was written; let the server send its implicit 200.
+			cw.decided = true
+			return nil
+		}
+		if err := cw.flushBuffer(false); err != nil {
+			return err
+		}
+	}
+	if cw.encoder == nil {
+		return nil
+	}
+
+	err := cw.encoder.Close()
+	cw.encoder = nil
+	if cw.release != nil {
+		cw.release()
+		cw.release = nil
+	}
+	return err
+}
+
+func (cw *compressResponseWriter) Flush() {
+	if !cw.decided && cw.wroteHeader {
+		_ = cw.flushBuffer(len(cw.buf) > 0 && cw.shouldCompress())
+	}
+	if f, ok := cw.encoder.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (cw *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hj, ok := cw.ResponseWriter.(http.Hijacker); ok {
+		return hj.Hijack()
+	}
+	return nil, nil, errors.New("underlying response writer does not support hijacking")
+}
+
+func (cw *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
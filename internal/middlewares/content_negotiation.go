@@ -0,0 +1,36 @@
+package middlewares
+
+import (
+	"log/slog"
+	"net/http"
+	"pinstack-api-gateway/internal/logger"
+	"pinstack-api-gateway/internal/utils"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// ContentNegotiationMiddleware selects the response codec from the Accept header.
+// Requests that accept none of JSON, MessagePack or protobuf are rejected with 406, as are protobuf
+// requests to endpoints whose payload has no pinstack-proto-definitions message type.
+func ContentNegotiationMiddleware(log *logger.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept")
+
+			accept := r.Header.Get("Accept")
+			codec, ok := utils.NegotiateCodec(accept)
+			if !ok {
+				log.Debug("unsupported media type requested",
+					slog.String("request_id", middleware.GetReqID(r.Context())),
+					slog.String("accept", accept),
+				)
+				utils.SendError(w, http.StatusNotAcceptable, "not acceptable")
+				return
+			}
+
+			next.ServeHTTP(utils.NewCodecResponseWriter(w, codec), r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
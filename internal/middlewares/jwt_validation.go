@@ -27,12 +27,7 @@ type Claims struct {
 func JWTValidationMiddleware(secretKey string, log *logger.Logger) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
-			requestID := middleware.GetReqID(r.Context())
-			entry := log.With(
-				slog.String("request_id", requestID),
-				slog.String("method", r.Method),
-				slog.String("path", r.URL.Path),
-			)
+			entry := requestLogEntry(log, r)
 
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
@@ -41,52 +36,93 @@ func JWTValidationMiddleware(secretKey string, log *logger.Logger) func(next htt
 				return
 			}
 
-			parts := strings.Split(authHeader, " ")
-			if len(parts) != 2 || parts[0] != "Bearer" {
-				entry.Error("invalid authorization header format")
-				utils.SendError(w, http.StatusUnauthorized, custom_errors.ErrInvalidToken.Error())
+			claims, ok := authenticate(w, entry, authHeader, secretKey)
+			if !ok {
 				return
 			}
 
-			parser := jwt.NewParser(
-				jwt.WithValidMethods([]string{"HS256"}),
-				jwt.WithLeeway(5*time.Second),
-			)
-
-			token, err := parser.ParseWithClaims(parts[1], &Claims{}, func(token *jwt.Token) (interface{}, error) {
-				return []byte(secretKey), nil
-			})
-
-			if err != nil {
-				entry.Error("token validation failed", slog.String("error", err.Error()))
-				switch {
-				case errors.Is(err, jwt.ErrTokenExpired):
-					utils.SendError(w, http.StatusUnauthorized, custom_errors.ErrTokenExpired.Error())
-				case errors.Is(err, jwt.ErrTokenMalformed):
-					utils.SendError(w, http.StatusUnauthorized, custom_errors.ErrInvalidToken.Error())
-				default:
-					utils.SendError(w, http.StatusUnauthorized, custom_errors.ErrUnauthenticated.Error())
-				}
+			ctx := context.WithValue(r.Context(), ClaimsKey, claims)
+			next.ServeHTTP(w, r.WithContext(ctx))
+
+			entry.Info("token validation completed successfully", slog.Int64("user_id", claims.UserID))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// OptionalJWTMiddleware lets anonymous requests through without claims.
+// A request that does send an Authorization header must carry a valid token, exactly as with JWTValidationMiddleware.
+func OptionalJWTMiddleware(secretKey string, log *logger.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" {
+				next.ServeHTTP(w, r)
 				return
 			}
 
-			claims, ok := token.Claims.(*Claims)
+			claims, ok := authenticate(w, requestLogEntry(log, r), authHeader, secretKey)
 			if !ok {
-				entry.Error("invalid token claims")
-				utils.SendError(w, http.StatusUnauthorized, custom_errors.ErrInvalidToken.Error())
 				return
 			}
 
 			ctx := context.WithValue(r.Context(), ClaimsKey, claims)
 			next.ServeHTTP(w, r.WithContext(ctx))
-
-			entry.Info("token validation completed successfully", slog.Int64("user_id", claims.UserID))
 		}
 
 		return http.HandlerFunc(fn)
 	}
 }
 
+func requestLogEntry(log *logger.Logger, r *http.Request) *slog.Logger {
+	return log.With(
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+	)
+}
+
+// authenticate validates a bearer token and writes the 401 response itself when it is rejected.
+func authenticate(w http.ResponseWriter, entry *slog.Logger, authHeader, secretKey string) (*Claims, bool) {
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		entry.Error("invalid authorization header format")
+		utils.SendError(w, http.StatusUnauthorized, custom_errors.ErrInvalidToken.Error())
+		return nil, false
+	}
+
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{"HS256"}),
+		jwt.WithLeeway(5*time.Second),
+	)
+
+	token, err := parser.ParseWithClaims(parts[1], &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		return []byte(secretKey), nil
+	})
+
+	if err != nil {
+		entry.Error("token validation failed", slog.String("error", err.Error()))
+		switch {
+		case errors.Is(err, jwt.ErrTokenExpired):
+			utils.SendError(w, http.StatusUnauthorized, custom_errors.ErrTokenExpired.Error())
+		case errors.Is(err, jwt.ErrTokenMalformed):
+			utils.SendError(w, http.StatusUnauthorized, custom_errors.ErrInvalidToken.Error())
+		default:
+			utils.SendError(w, http.StatusUnauthorized, custom_errors.ErrUnauthenticated.Error())
+		}
+		return nil, false
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok {
+		entry.Error("invalid token claims")
+		utils.SendError(w, http.StatusUnauthorized, custom_errors.ErrInvalidToken.Error())
+		return nil, false
+	}
+	return claims, true
+}
+
 func GetClaimsFromContext(ctx context.Context) (*Claims, error) {
 	claims, ok := ctx.Value(ClaimsKey).(*Claims)
 	if !ok {
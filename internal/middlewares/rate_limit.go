@@ -0,0 +1,93 @@
+package middlewares
+
+import (
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"pinstack-api-gateway/internal/logger"
+	"pinstack-api-gateway/internal/metrics"
+	"pinstack-api-gateway/internal/utils"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// rateLimitSweepSize is the number of tracked callers above which expired windows are dropped.
+const rateLimitSweepSize = 10_000
+
+// RateLimiter counts requests per caller in fixed windows.
+type RateLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu      sync.Mutex
+	windows map[string]*rateWindow
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{limit: limit, window: window, windows: make(map[string]*rateWindow)}
+}
+
+// Allow records a request by key and reports whether it is within the limit. When it is not,
+// it also returns how long until the key's window resets.
+func (l *RateLimiter) Allow(key string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.windows) >= rateLimitSweepSize {
+		for k, w := range l.windows {
+			if now.Sub(w.start) >= l.window {
+				delete(l.windows, k)
+			}
+		}
+	}
+
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.window {
+		w = &rateWindow{start: now}
+		l.windows[key] = w
+	}
+	if w.count >= l.limit {
+		return false, w.start.Add(l.window).Sub(now)
+	}
+	w.count++
+	return true, 0
+}
+
+// RateLimitMiddleware rejects requests beyond the limiter's allowance with 429 Too Many Requests.
+// Authenticated callers are limited per user and anonymous ones per client address,
+// so it should run after the JWT middleware when the route is authenticated.
+func RateLimitMiddleware(limiter *RateLimiter, metricsProvider metrics.MetricsProvider, log *logger.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			endpoint := r.URL.Path
+			if routeCtx := chi.RouteContext(r.Context()); routeCtx != nil && routeCtx.RoutePattern() != "" {
+				endpoint = routeCtx.RoutePattern()
+			}
+
+			key := "addr:" + r.RemoteAddr
+			if claims, err := GetClaimsFromContext(r.Context()); err == nil {
+				key = "user:" + strconv.FormatInt(claims.UserID, 10)
+			}
+
+			metricsProvider.IncRateLimitHits(endpoint)
+			allowed, retryAfter := limiter.Allow(key, time.Now())
+			if !allowed {
+				metricsProvider.IncRateLimitExceeded(endpoint)
+				requestLogEntry(log, r).Debug("Rate limit exceeded", slog.String("key", key))
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				utils.SendError(w, http.StatusTooManyRequests, custom_errors.ErrRateLimitExceeded.Error())
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
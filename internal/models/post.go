@@ -39,14 +39,23 @@ type PostDetailed struct {
 }
 
 type PostFilters struct {
-	AuthorID      *int64     `json:"author_id,omitempty"`
-	TagNames      []string   `json:"tag_names,omitempty"`
-	CreatedAfter  *time.Time `json:"created_after,omitempty"`
-	CreatedBefore *time.Time `json:"created_before,omitempty"`
-	Limit         *int       `json:"limit,omitempty"`
-	Offset        *int       `json:"offset,omitempty"`
+	AuthorID      *int64       `json:"author_id,omitempty"`
+	TagNames      []string     `json:"tag_names,omitempty"`
+	TagMode       TagMatchMode `json:"tag_mode,omitempty"`
+	CreatedAfter  *time.Time   `json:"created_after,omitempty"`
+	CreatedBefore *time.Time   `json:"created_before,omitempty"`
+	Limit         *int         `json:"limit,omitempty"`
+	Offset        *int         `json:"offset,omitempty"`
 }
 
+// TagMatchMode controls whether a post must carry any or all of PostFilters.TagNames.
+type TagMatchMode string
+
+const (
+	TagMatchAny TagMatchMode = "any"
+	TagMatchAll TagMatchMode = "all"
+)
+
 type PostMedia struct {
 	ID        int64     `json:"id" validate:"required"`
 	PostID    int64     `json:"post_id" validate:"required"`
@@ -63,6 +72,13 @@ const (
 	MediaTypeVideo MediaType = "video"
 )
 
+// MediaVariants holds the URLs of the sizes generated for an image uploaded through the gateway.
+type MediaVariants struct {
+	Thumbnail string `json:"thumbnail"`
+	Medium    string `json:"medium"`
+	Full      string `json:"full"`
+}
+
 type PostTag struct {
 	PostID int64 `json:"post_id" validate:"required"`
 	TagID  int64 `json:"tag_id" validate:"required"`
@@ -132,6 +148,13 @@ func PostFiltersToProto(filters *PostFilters) *pb.ListPostsRequest {
 	if filters.AuthorID != nil {
 		authorId = *filters.AuthorID
 	}
+	var createdAfter, createdBefore *timestamppb.Timestamp
+	if filters.CreatedAfter != nil {
+		createdAfter = timestamppb.New(*filters.CreatedAfter)
+	}
+	if filters.CreatedBefore != nil {
+		createdBefore = timestamppb.New(*filters.CreatedBefore)
+	}
 	var offset, limit int32
 	if filters.Offset != nil {
 		offset = int32(*filters.Offset)
@@ -140,9 +163,12 @@ func PostFiltersToProto(filters *PostFilters) *pb.ListPostsRequest {
 		limit = int32(*filters.Limit)
 	}
 	return &pb.ListPostsRequest{
-		AuthorId: authorId,
-		Offset:   offset,
-		Limit:    limit,
+		AuthorId:      authorId,
+		TagNames:      filters.TagNames,
+		CreatedAfter:  createdAfter,
+		CreatedBefore: createdBefore,
+		Offset:        offset,
+		Limit:         limit,
 	}
 }
 
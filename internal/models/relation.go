@@ -14,15 +14,25 @@ type Follower struct {
 }
 
 type RelationUser struct {
-	ID        int64   `json:"id"`
-	Username  string  `json:"username"`
-	AvatarURL *string `json:"avatar_url,omitempty"`
+	ID             int64   `json:"id"`
+	Username       string  `json:"username"`
+	AvatarURL      *string `json:"avatar_url,omitempty"`
+	FullName       *string `json:"full_name,omitempty"`
+	Bio            *string `json:"bio,omitempty"`
+	IsFollowedByMe *bool   `json:"is_followed_by_me,omitempty"`
 }
 
-func RelationUserFromProto(u *pb.User) *RelationUser {
-	return &RelationUser{
-		ID:        u.FollowerId,
-		Username:  u.Username,
+// RelationUserFromProto converts an entry of a follower or followee list owned by ownerID.
+// The relation service reuses one User message for both lists and its only ID field is named follower_id;
+// it holds the listed user's ID. An entry carrying the owner's own ID cannot be right (users cannot follow
+// themselves), so its ID is left as 0 and the relation client resolves it by username.
+func RelationUserFromProto(u *pb.User, ownerID int64) *RelationUser {
+	user := &RelationUser{
+		Username:  u.GetUsername(),
 		AvatarURL: u.AvatarUrl,
 	}
+	if id := u.GetFollowerId(); id != ownerID {
+		user.ID = id
+	}
+	return user
 }
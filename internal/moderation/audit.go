@@ -0,0 +1,97 @@
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"pinstack-api-gateway/internal/logger"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// AuditEntry records one moderation decision.
+type AuditEntry struct {
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"request_id,omitempty"`
+	Operation Operation `json:"operation"`
+	UserID    int64     `json:"user_id"`
+	PostID    int64     `json:"post_id,omitempty"`
+	Action    Action    `json:"action"`
+	Reasons   []Reason  `json:"reasons,omitempty"`
+}
+
+// Auditor records every moderation decision, including passes.
+type Auditor interface {
+	Record(ctx context.Context, entry AuditEntry)
+}
+
+func newAuditEntry(ctx context.Context, c *Content, d Decision) AuditEntry {
+	return AuditEntry{
+		Time:      time.Now().UTC(),
+		RequestID: middleware.GetReqID(ctx),
+		Operation: c.Operation,
+		UserID:    c.UserID,
+		PostID:    c.PostID,
+		Action:    d.Action,
+		Reasons:   d.Reasons,
+	}
+}
+
+// LogAuditor writes decisions to the application log.
+type LogAuditor struct {
+	log *logger.Logger
+}
+
+func NewLogAuditor(log *logger.Logger) *LogAuditor {
+	return &LogAuditor{log: log}
+}
+
+func (a *LogAuditor) Record(ctx context.Context, entry AuditEntry) {
+	a.log.Info("moderation decision",
+		slog.String("request_id", entry.RequestID),
+		slog.String("operation", string(entry.Operation)),
+		slog.Int64("user_id", entry.UserID),
+		slog.Int64("post_id", entry.PostID),
+		slog.String("action", entry.Action.String()),
+		slog.Any("reasons", entry.Reasons),
+	)
+}
+
+// FileAuditor appends decisions to a file as JSON lines.
+type FileAuditor struct {
+	mu   sync.Mutex
+	file *os.File
+	log  *logger.Logger
+}
+
+func NewFileAuditor(path string, log *logger.Logger) (*FileAuditor, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, err
+	}
+	return &FileAuditor{file: file, log: log}, nil
+}
+
+func (a *FileAuditor) Record(ctx context.Context, entry AuditEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		a.log.Error("Failed to encode moderation audit entry", slog.String("error", err.Error()))
+		return
+	}
+	line = append(line, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.file.Write(line); err != nil {
+		a.log.Error("Failed to write moderation audit entry", slog.String("error", err.Error()))
+	}
+}
+
+func (a *FileAuditor) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.file.Close()
+}
@@ -0,0 +1,84 @@
+package moderation
+
+// Action is the outcome of a moderation rule. Later actions are more severe.
+type Action int
+
+const (
+	Pass Action = iota
+	Flag
+	Reject
+)
+
+func (a Action) String() string {
+	switch a {
+	case Flag:
+		return "flag"
+	case Reject:
+		return "reject"
+	default:
+		return "pass"
+	}
+}
+
+func (a Action) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// Operation identifies what a moderated request is doing.
+type Operation string
+
+const (
+	OperationCreatePost Operation = "create_post"
+	OperationUpdatePost Operation = "update_post"
+)
+
+// Content is the user supplied text and media of a post. Fields left empty are not checked,
+// so an update only moderates what it changes.
+type Content struct {
+	Operation Operation
+	UserID    int64
+	PostID    int64
+	Title     string
+	Text      string
+	Tags      []string
+	MediaURLs []string
+}
+
+// Reason explains why a rule flagged or rejected content.
+type Reason struct {
+	Rule    string `json:"rule"`
+	Action  Action `json:"action"`
+	Message string `json:"message"`
+}
+
+// Decision is the combined outcome of all rules: the most severe action and every reason behind it.
+type Decision struct {
+	Action  Action   `json:"action"`
+	Reasons []Reason `json:"reasons,omitempty"`
+}
+
+// Rejected reports whether the content must not be stored.
+func (d Decision) Rejected() bool {
+	return d.Action == Reject
+}
+
+// Rule checks content. It returns a reason for each problem found, or none if the content passes.
+type Rule interface {
+	Name() string
+	Check(c *Content) []Reason
+}
+
+// evaluate runs rules against c and combines their findings.
+func evaluate(rules []Rule, c *Content) Decision {
+	decision := Decision{Action: Pass}
+	for _, rule := range rules {
+		for _, reason := range rule.Check(c) {
+			if reason.Action == Pass {
+				continue
+			}
+			decision.Reasons = append(decision.Reasons, reason)
+			decision.Action = max(decision.Action, reason.Action)
+		}
+	}
+	return decision
+}
@@ -0,0 +1,193 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"pinstack-api-gateway/internal/logger"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ruleConfig is one entry of the rules file.
+type ruleConfig struct {
+	Name    string   `yaml:"name"`
+	Type    string   `yaml:"type"`
+	Action  string   `yaml:"action"`
+	Words   []string `yaml:"words"`
+	Domains []string `yaml:"domains"`
+	Max     int      `yaml:"max"`
+}
+
+type rulesFile struct {
+	Rules []ruleConfig `yaml:"rules"`
+}
+
+// LoadRules reads rules from a YAML file of the form
+//
+//	rules:
+//	  - name: profanity
+//	    type: banned_words      # banned_words, domain_blocklist or max_links
+//	    action: reject          # reject, flag or pass
+//	    words: [...]
+func LoadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file rulesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	rules := make([]Rule, 0, len(file.Rules))
+	for i, cfg := range file.Rules {
+		if cfg.Name == "" {
+			cfg.Name = fmt.Sprintf("%s-%d", cfg.Type, i+1)
+		}
+		action, err := parseAction(cfg.Action)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", cfg.Name, err)
+		}
+		switch cfg.Type {
+		case "banned_words":
+			rules = append(rules, NewBannedWordsRule(cfg.Name, action, cfg.Words))
+		case "domain_blocklist":
+			rules = append(rules, NewDomainBlocklistRule(cfg.Name, action, cfg.Domains))
+		case "max_links":
+			rules = append(rules, NewMaxLinksRule(cfg.Name, action, cfg.Max))
+		default:
+			return nil, fmt.Errorf("rule %s: unknown type %q", cfg.Name, cfg.Type)
+		}
+	}
+	return rules, nil
+}
+
+func parseAction(s string) (Action, error) {
+	switch s {
+	case "reject", "":
+		return Reject, nil
+	case "flag":
+		return Flag, nil
+	case "pass":
+		return Pass, nil
+	default:
+		return Pass, fmt.Errorf("unknown action %q", s)
+	}
+}
+
+// Moderator checks content against rules loaded from a file and audits every decision.
+// The file is polled for changes; a file that fails to load leaves the previous rules in place.
+type Moderator struct {
+	path  string
+	rules atomic.Pointer[[]Rule]
+
+	mu      sync.Mutex // serialises reloads and guards modTime
+	modTime time.Time
+	auditor Auditor
+	log     *logger.Logger
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewModerator loads the rules file and, if reloadInterval is positive, watches it for changes.
+func NewModerator(path string, reloadInterval time.Duration, auditor Auditor, log *logger.Logger) (*Moderator, error) {
+	m := &Moderator{
+		path:    path,
+		auditor: auditor,
+		log:     log,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if err := m.Reload(); err != nil {
+		return nil, err
+	}
+	if reloadInterval > 0 {
+		go m.watch(reloadInterval)
+	} else {
+		close(m.done)
+	}
+	return m, nil
+}
+
+// Moderate decides whether content may be stored and records the decision.
+func (m *Moderator) Moderate(ctx context.Context, c *Content) Decision {
+	decision := evaluate(*m.rules.Load(), c)
+	m.auditor.Record(ctx, newAuditEntry(ctx, c, decision))
+	return decision
+}
+
+// Reload replaces the rules with the current contents of the rules file.
+func (m *Moderator) Reload() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.reload()
+}
+
+func (m *Moderator) reload() error {
+	stat, err := os.Stat(m.path)
+	if err != nil {
+		return err
+	}
+	rules, err := LoadRules(m.path)
+	if err != nil {
+		return err
+	}
+	m.rules.Store(&rules)
+	m.modTime = stat.ModTime()
+	m.log.Info("Moderation rules loaded", slog.String("path", m.path), slog.Int("rules", len(rules)))
+	return nil
+}
+
+func (m *Moderator) watch(interval time.Duration) {
+	defer close(m.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			stat, err := os.Stat(m.path)
+			if err != nil {
+				m.log.Warn("Failed to stat moderation rules", slog.String("path", m.path), slog.String("error", err.Error()))
+				continue
+			}
+			m.reloadIfChanged(stat.ModTime())
+		}
+	}
+}
+
+func (m *Moderator) reloadIfChanged(modTime time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if modTime.Equal(m.modTime) {
+		return
+	}
+	if err := m.reload(); err != nil {
+		m.log.Error("Failed to reload moderation rules, keeping previous rules", slog.String("path", m.path), slog.String("error", err.Error()))
+		// Do not retry the same broken file every tick.
+		m.modTime = modTime
+	}
+}
+
+// Close stops watching the rules file and closes the auditor if it holds resources.
+func (m *Moderator) Close(ctx context.Context) error {
+	m.closeOnce.Do(func() { close(m.stop) })
+	select {
+	case <-m.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if closer, ok := m.auditor.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
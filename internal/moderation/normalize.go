@@ -0,0 +1,96 @@
+package moderation
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// confusables maps letters from other scripts that render like Latin letters onto them.
+var confusables = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'в': 'b', 'е': 'e', 'ё': 'e', 'к': 'k', 'м': 'm', 'н': 'h', 'о': 'o', 'р': 'p',
+	'с': 'c', 'т': 't', 'у': 'y', 'х': 'x', 'ѕ': 's', 'і': 'i', 'ї': 'i', 'ј': 'j', 'ԁ': 'd',
+	'ɡ': 'g', 'һ': 'h', 'ԛ': 'q', 'ԝ': 'w',
+	// Greek
+	'α': 'a', 'β': 'b', 'ε': 'e', 'η': 'n', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o', 'ρ': 'p',
+	'τ': 't', 'υ': 'u', 'χ': 'x', 'ω': 'w',
+	// Latin lookalikes
+	'ı': 'i', 'ł': 'l', 'ø': 'o', 'đ': 'd', 'ß': 's',
+}
+
+// leet maps digits and symbols commonly substituted for letters.
+var leet = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '8': 'b', '9': 'g',
+	'@': 'a', '$': 's', '!': 'i', '|': 'i', '+': 't', '€': 'e',
+}
+
+// normalize folds text to lower-case Latin letters for matching: compatibility forms are
+// decomposed, diacritics dropped and lookalike letters mapped to the letters they imitate.
+// With leetspeak set, digits and symbols standing in for letters are mapped too.
+// Everything else becomes a space.
+func normalize(text string, leetspeak bool) string {
+	decomposed := norm.NFKD.String(strings.ToLower(text))
+	var b strings.Builder
+	b.Grow(len(decomposed))
+	for _, r := range decomposed {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if mapped, ok := confusables[r]; ok {
+			r = mapped
+		} else if mapped, ok := leet[r]; ok && leetspeak {
+			r = mapped
+		}
+		if unicode.IsLetter(r) {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte(' ')
+		}
+	}
+	return b.String()
+}
+
+// tokenize splits normalized text into words. It also returns a second sequence in which runs
+// of single letters are joined, so that "b a d" and "b.a.d" match "bad".
+func tokenize(text string, leetspeak bool) (words, squashed []string) {
+	words = strings.Fields(normalize(text, leetspeak))
+	var run strings.Builder
+	flush := func() {
+		if run.Len() > 0 {
+			squashed = append(squashed, run.String())
+			run.Reset()
+		}
+	}
+	for _, w := range words {
+		if len([]rune(w)) == 1 {
+			run.WriteString(w)
+			continue
+		}
+		flush()
+		squashed = append(squashed, w)
+	}
+	flush()
+	return words, squashed
+}
+
+// containsSequence reports whether needle occurs as consecutive words in haystack.
+func containsSequence(haystack, needle []string) bool {
+	if len(needle) == 0 {
+		return false
+	}
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		match := true
+		for j, w := range needle {
+			if haystack[i+j] != w {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
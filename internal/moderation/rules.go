@@ -0,0 +1,137 @@
+package moderation
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	linkRegex = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>()\[\]"']+`)
+	// hostRegex also finds bare domains such as example.com, which are not counted as links
+	// but are still checked against the blocklist.
+	hostRegex = regexp.MustCompile(`(?i)\b(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,}\b`)
+)
+
+// BannedWordsRule matches words and phrases after normalising away case, diacritics,
+// lookalike letters and leetspeak.
+type BannedWordsRule struct {
+	name    string
+	action  Action
+	phrases [][]string
+}
+
+func NewBannedWordsRule(name string, action Action, words []string) *BannedWordsRule {
+	rule := &BannedWordsRule{name: name, action: action}
+	for _, w := range words {
+		if phrase := strings.Fields(normalize(w, false)); len(phrase) > 0 {
+			rule.phrases = append(rule.phrases, phrase)
+		}
+	}
+	return rule
+}
+
+func (r *BannedWordsRule) Name() string { return r.name }
+
+func (r *BannedWordsRule) Check(c *Content) []Reason {
+	text := strings.Join(append([]string{c.Title, c.Text}, c.Tags...), " \n ")
+	// Text is matched both as written and with leetspeak undone, since symbols such as "!"
+	// are punctuation at least as often as they are letters.
+	for _, leetspeak := range []bool{false, true} {
+		words, squashed := tokenize(text, leetspeak)
+		for _, phrase := range r.phrases {
+			if containsSequence(words, phrase) || containsSequence(squashed, phrase) {
+				// The matched word is not echoed back, so the reason can be shown to the author as is.
+				return []Reason{{Rule: r.name, Action: r.action, Message: "content contains a banned word"}}
+			}
+		}
+	}
+	return nil
+}
+
+// DomainBlocklistRule matches links in the text and media URLs whose host is a blocked domain or a subdomain of one.
+type DomainBlocklistRule struct {
+	name    string
+	action  Action
+	domains map[string]struct{}
+}
+
+func NewDomainBlocklistRule(name string, action Action, domains []string) *DomainBlocklistRule {
+	rule := &DomainBlocklistRule{name: name, action: action, domains: make(map[string]struct{}, len(domains))}
+	for _, d := range domains {
+		if d = normalizeHost(d); d != "" {
+			rule.domains[d] = struct{}{}
+		}
+	}
+	return rule
+}
+
+func (r *DomainBlocklistRule) Name() string { return r.name }
+
+func (r *DomainBlocklistRule) Check(c *Content) []Reason {
+	var hosts []string
+	for _, field := range []string{c.Title, c.Text} {
+		hosts = append(hosts, hostRegex.FindAllString(field, -1)...)
+	}
+	for _, raw := range c.MediaURLs {
+		if u, err := url.Parse(raw); err == nil {
+			hosts = append(hosts, u.Hostname())
+		}
+	}
+
+	var reasons []Reason
+	seen := make(map[string]struct{})
+	for _, host := range hosts {
+		blocked, ok := r.blocked(normalizeHost(host))
+		if !ok {
+			continue
+		}
+		if _, dup := seen[blocked]; dup {
+			continue
+		}
+		seen[blocked] = struct{}{}
+		reasons = append(reasons, Reason{Rule: r.name, Action: r.action, Message: fmt.Sprintf("links to blocked domain %s", blocked)})
+	}
+	return reasons
+}
+
+// blocked returns the blocklist entry host falls under, checking the host itself and each parent domain.
+func (r *DomainBlocklistRule) blocked(host string) (string, bool) {
+	for host != "" {
+		if _, ok := r.domains[host]; ok {
+			return host, true
+		}
+		_, parent, found := strings.Cut(host, ".")
+		if !found {
+			break
+		}
+		host = parent
+	}
+	return "", false
+}
+
+// MaxLinksRule limits the number of links in the title and text.
+type MaxLinksRule struct {
+	name   string
+	action Action
+	max    int
+}
+
+func NewMaxLinksRule(name string, action Action, max int) *MaxLinksRule {
+	return &MaxLinksRule{name: name, action: action, max: max}
+}
+
+func (r *MaxLinksRule) Name() string { return r.name }
+
+func (r *MaxLinksRule) Check(c *Content) []Reason {
+	count := len(linkRegex.FindAllString(c.Title, -1)) + len(linkRegex.FindAllString(c.Text, -1))
+	if count <= r.max {
+		return nil
+	}
+	return []Reason{{Rule: r.name, Action: r.action, Message: fmt.Sprintf("contains %d links, at most %d allowed", count, r.max)}}
+}
+
+func normalizeHost(host string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+}
@@ -0,0 +1,252 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"log/slog"
+	notification_client "pinstack-api-gateway/internal/clients/notification"
+	"pinstack-api-gateway/internal/logger"
+	"sync"
+	"time"
+)
+
+// Notification is a message queued for delivery through the notification service.
+// Notifications with the same non-empty DedupKey are delivered at most once per dedup window,
+// which is DedupWindow when set and Options.DedupWindow otherwise.
+type Notification struct {
+	UserID      int64
+	Type        string
+	Payload     []byte
+	DedupKey    string
+	DedupWindow time.Duration
+}
+
+type Options struct {
+	Workers     int
+	QueueSize   int
+	MaxAttempts int
+	RetryDelay  time.Duration
+	SendTimeout time.Duration
+	DedupWindow time.Duration
+}
+
+// Dispatcher delivers notifications in the background so callers never wait on, or fail because of,
+// the notification service. Transient failures are retried with exponential backoff.
+type Dispatcher struct {
+	client notification_client.NotificationClient
+	opts   Options
+	log    *logger.Logger
+
+	queue chan Notification
+	wg    sync.WaitGroup
+	stop  chan struct{}
+	once  sync.Once
+
+	mu      sync.Mutex
+	seen    map[string]time.Time // dedup key -> end of its window
+	revoked map[string]struct{}
+}
+
+func NewDispatcher(client notification_client.NotificationClient, opts Options, log *logger.Logger) *Dispatcher {
+	if opts.Workers <= 0 {
+		opts.Workers = 2
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1000
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 3
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = 500 * time.Millisecond
+	}
+	if opts.SendTimeout <= 0 {
+		opts.SendTimeout = 5 * time.Second
+	}
+
+	d := &Dispatcher{
+		client:  client,
+		opts:    opts,
+		log:     log,
+		queue:   make(chan Notification, opts.QueueSize),
+		stop:    make(chan struct{}),
+		seen:    make(map[string]time.Time),
+		revoked: make(map[string]struct{}),
+	}
+	for i := 0; i < opts.Workers; i++ {
+		d.wg.Add(1)
+		go d.work()
+	}
+	return d
+}
+
+// Notify queues n for delivery. It never blocks; when the queue is full the notification is dropped.
+func (d *Dispatcher) Notify(n Notification) {
+	window := n.DedupWindow
+	if window <= 0 {
+		window = d.opts.DedupWindow
+	}
+	if n.DedupKey != "" && !d.claim(n.DedupKey, window) {
+		d.log.Debug("Duplicate notification skipped", slog.String("type", n.Type), slog.String("dedup_key", n.DedupKey))
+		return
+	}
+
+	select {
+	case <-d.stop:
+		return
+	default:
+	}
+
+	select {
+	case d.queue <- n:
+	default:
+		d.log.Warn("Notification queue full, dropping notification", slog.String("type", n.Type), slog.Int64("user_id", n.UserID))
+	}
+}
+
+// Revoke cancels a queued or retrying notification that has not been delivered yet.
+func (d *Dispatcher) Revoke(dedupKey string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.seen[dedupKey]; ok {
+		d.revoked[dedupKey] = struct{}{}
+	}
+}
+
+// Close stops accepting notifications and waits for queued ones to be delivered or for ctx to end.
+func (d *Dispatcher) Close(ctx context.Context) error {
+	d.once.Do(func() { close(d.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// work delivers queued notifications until the dispatcher stops, then drains what is left.
+// The queue is never closed, so a Notify racing with Close cannot send on a closed channel;
+// a notification queued after the drain is simply dropped.
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+	for {
+		select {
+		case n := <-d.queue:
+			d.deliver(n)
+		case <-d.stop:
+			for {
+				select {
+				case n := <-d.queue:
+					d.deliver(n)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(n Notification) {
+	delay := d.opts.RetryDelay
+	for attempt := 1; attempt <= d.opts.MaxAttempts; attempt++ {
+		if d.discardIfRevoked(n.DedupKey) {
+			d.log.Debug("Notification revoked before delivery", slog.String("type", n.Type), slog.String("dedup_key", n.DedupKey))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), d.opts.SendTimeout)
+		_, err := d.client.SendNotification(ctx, n.UserID, n.Type, n.Payload)
+		cancel()
+		if err == nil {
+			d.delivered(n.DedupKey)
+			return
+		}
+		if !retryable(err) || attempt == d.opts.MaxAttempts {
+			d.log.Error("Failed to deliver notification",
+				slog.String("type", n.Type),
+				slog.Int64("user_id", n.UserID),
+				slog.Int("attempts", attempt),
+				slog.String("error", err.Error()),
+			)
+			d.delivered(n.DedupKey)
+			return
+		}
+
+		d.log.Warn("Notification delivery failed, retrying", slog.String("type", n.Type), slog.Int("attempt", attempt), slog.String("error", err.Error()))
+		select {
+		case <-time.After(delay):
+		case <-d.stop:
+			// Shutting down: make one last attempt without waiting.
+		}
+		delay *= 2
+	}
+}
+
+// claim records dedupKey for window and reports whether it was not already claimed inside its window.
+func (d *Dispatcher) claim(dedupKey string, window time.Duration) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	for key, until := range d.seen {
+		if now.After(until) {
+			delete(d.seen, key)
+			delete(d.revoked, key)
+		}
+	}
+
+	if _, ok := d.seen[dedupKey]; ok {
+		// A revoked notification that is claimed again (follow, unfollow, follow) is simply reinstated.
+		if _, revoked := d.revoked[dedupKey]; revoked {
+			delete(d.revoked, dedupKey)
+		}
+		return false
+	}
+	d.seen[dedupKey] = now.Add(window)
+	return true
+}
+
+// discardIfRevoked reports whether the notification was revoked. A discarded key is forgotten,
+// so the same action repeated later is notified again.
+func (d *Dispatcher) discardIfRevoked(dedupKey string) bool {
+	if dedupKey == "" {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.revoked[dedupKey]; !ok {
+		return false
+	}
+	delete(d.revoked, dedupKey)
+	delete(d.seen, dedupKey)
+	return true
+}
+
+// delivered ends tracking of revocations; the key stays in seen until the dedup window expires.
+func (d *Dispatcher) delivered(dedupKey string) {
+	if dedupKey == "" {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.revoked, dedupKey)
+}
+
+func retryable(err error) bool {
+	switch {
+	case errors.Is(err, custom_errors.ErrExternalServiceUnavailable),
+		errors.Is(err, custom_errors.ErrExternalServiceTimeout),
+		errors.Is(err, custom_errors.ErrExternalServiceError),
+		errors.Is(err, custom_errors.ErrNotificationLimitExceeded):
+		return true
+	default:
+		return false
+	}
+}
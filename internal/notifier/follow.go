@@ -0,0 +1,58 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"pinstack-api-gateway/internal/logger"
+	"time"
+
+	"github.com/soloda1/pinstack-proto-definitions/events"
+)
+
+// TypeNewFollower is the notification type sent to a user who gained a follower.
+const TypeNewFollower = "new_follower"
+
+// FollowNotifier notifies followees about new followers. A follow undone before the notification
+// goes out is not delivered, and repeated follows of the same user within the dedup window notify once.
+// A zero dedupWindow uses the dispatcher's.
+type FollowNotifier struct {
+	dispatcher  *Dispatcher
+	dedupWindow time.Duration
+	log         *logger.Logger
+}
+
+func NewFollowNotifier(dispatcher *Dispatcher, dedupWindow time.Duration, log *logger.Logger) *FollowNotifier {
+	return &FollowNotifier{
+		dispatcher:  dispatcher,
+		dedupWindow: dedupWindow,
+		log:         log,
+	}
+}
+
+func (n *FollowNotifier) AfterFollow(followerID, followeeID int64) {
+	payload, err := json.Marshal(events.FollowCreatedPayload{
+		FollowerID:  followerID,
+		FolloweeID:  followeeID,
+		Timestamptz: time.Now().UTC(),
+	})
+	if err != nil {
+		n.log.Error("Failed to encode follow notification payload", slog.String("error", err.Error()))
+		return
+	}
+	n.dispatcher.Notify(Notification{
+		UserID:      followeeID,
+		Type:        TypeNewFollower,
+		Payload:     payload,
+		DedupKey:    followDedupKey(followerID, followeeID),
+		DedupWindow: n.dedupWindow,
+	})
+}
+
+func (n *FollowNotifier) AfterUnfollow(followerID, followeeID int64) {
+	n.dispatcher.Revoke(followDedupKey(followerID, followeeID))
+}
+
+func followDedupKey(followerID, followeeID int64) string {
+	return fmt.Sprintf("%s:%d:%d", TypeNewFollower, followerID, followeeID)
+}
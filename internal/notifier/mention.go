@@ -0,0 +1,52 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"pinstack-api-gateway/internal/logger"
+	"time"
+)
+
+// TypePostMention is the notification type sent to a user mentioned in a post.
+const TypePostMention = "post_mention"
+
+// PostMentionPayload is the payload of a TypePostMention notification.
+type PostMentionPayload struct {
+	PostID      int64     `json:"post_id"`
+	AuthorID    int64     `json:"author_id"`
+	UserID      int64     `json:"user_id"`
+	Timestamptz time.Time `json:"timestamptz"`
+}
+
+// MentionNotifier notifies users mentioned in posts. A user is notified once per post within the dedup window.
+type MentionNotifier struct {
+	dispatcher *Dispatcher
+	log        *logger.Logger
+}
+
+func NewMentionNotifier(dispatcher *Dispatcher, log *logger.Logger) *MentionNotifier {
+	return &MentionNotifier{
+		dispatcher: dispatcher,
+		log:        log,
+	}
+}
+
+func (n *MentionNotifier) NotifyMention(postID, authorID, mentionedUserID int64) {
+	payload, err := json.Marshal(PostMentionPayload{
+		PostID:      postID,
+		AuthorID:    authorID,
+		UserID:      mentionedUserID,
+		Timestamptz: time.Now().UTC(),
+	})
+	if err != nil {
+		n.log.Error("Failed to encode mention notification payload", slog.String("error", err.Error()))
+		return
+	}
+	n.dispatcher.Notify(Notification{
+		UserID:   mentionedUserID,
+		Type:     TypePostMention,
+		Payload:  payload,
+		DedupKey: fmt.Sprintf("%s:%d:%d", TypePostMention, postID, mentionedUserID),
+	})
+}
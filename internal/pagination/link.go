@@ -0,0 +1,30 @@
+package pagination
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// LinkHeader builds an RFC 8288 Link header value from rel -> query overrides.
+// Each target keeps the request path and query, with the overrides applied; an empty override value removes the parameter.
+func LinkHeader(r *http.Request, links map[string]map[string]string, order ...string) string {
+	parts := make([]string, 0, len(order))
+	for _, rel := range order {
+		overrides, ok := links[rel]
+		if !ok {
+			continue
+		}
+		query := r.URL.Query()
+		for k, v := range overrides {
+			if v == "" {
+				query.Del(k)
+				continue
+			}
+			query.Set(k, v)
+		}
+		target := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+		parts = append(parts, "<"+target.String()+`>; rel="`+rel+`"`)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,118 @@
+package pagination
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+var ErrInvalidPagination = errors.New("invalid pagination parameters")
+
+// Params is a parsed page window. Page and Offset always describe the same window.
+type Params struct {
+	Page   int
+	Limit  int
+	Offset int
+
+	offsetStyle bool
+}
+
+// Meta is the pagination block shared by every list response.
+type Meta struct {
+	Total      int64 `json:"total"`
+	Page       int   `json:"page"`
+	Limit      int   `json:"limit"`
+	Offset     int   `json:"offset"`
+	TotalPages int   `json:"total_pages"`
+	HasMore    bool  `json:"has_more"`
+}
+
+// Parse reads page/offset and limit from the query string.
+// limit defaults to DefaultLimit and may not exceed MaxLimit; page and offset are mutually exclusive.
+func Parse(query url.Values) (Params, error) {
+	p := Params{Page: 1, Limit: DefaultLimit}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return p, ErrInvalidPagination
+		}
+		p.Limit = limit
+	}
+
+	pageStr, offsetStr := query.Get("page"), query.Get("offset")
+	switch {
+	case pageStr != "" && offsetStr != "":
+		return p, ErrInvalidPagination
+	case pageStr != "":
+		page, err := strconv.Atoi(pageStr)
+		if err != nil || page < 1 {
+			return p, ErrInvalidPagination
+		}
+		p.Page = page
+		p.Offset = (page - 1) * p.Limit
+	case offsetStr != "":
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return p, ErrInvalidPagination
+		}
+		p.Offset = offset
+		p.Page = offset/p.Limit + 1
+		p.offsetStyle = true
+	}
+
+	return p, nil
+}
+
+// PageAligned reports whether the window can be expressed as page/limit for page-based backends.
+func (p Params) PageAligned() bool {
+	return p.Offset%p.Limit == 0
+}
+
+// Meta builds the response pagination block for a window that returned count items out of total.
+func (p Params) Meta(total int64, count int) Meta {
+	totalPages := 0
+	if total > 0 {
+		totalPages = int((total + int64(p.Limit) - 1) / int64(p.Limit))
+	}
+	return Meta{
+		Total:      total,
+		Page:       p.Page,
+		Limit:      p.Limit,
+		Offset:     p.Offset,
+		TotalPages: totalPages,
+		HasMore:    int64(p.Offset+count) < total,
+	}
+}
+
+// SetLinkHeader writes RFC 8288 first/prev/next/last links using the same parameter style the client used.
+func (p Params) SetLinkHeader(w http.ResponseWriter, r *http.Request, meta Meta) {
+	links := make(map[string]map[string]string, 4)
+	window := func(offset int) map[string]string {
+		if p.offsetStyle {
+			return map[string]string{"offset": strconv.Itoa(offset), "limit": strconv.Itoa(p.Limit), "page": ""}
+		}
+		return map[string]string{"page": strconv.Itoa(offset/p.Limit + 1), "limit": strconv.Itoa(p.Limit), "offset": ""}
+	}
+
+	links["first"] = window(0)
+	if p.Offset > 0 {
+		links["prev"] = window(max(0, p.Offset-p.Limit))
+	}
+	if meta.HasMore {
+		links["next"] = window(p.Offset + p.Limit)
+	}
+	if meta.TotalPages > 0 {
+		links["last"] = window((meta.TotalPages - 1) * p.Limit)
+	}
+
+	if link := LinkHeader(r, links, "first", "prev", "next", "last"); link != "" {
+		w.Header().Set("Link", link)
+	}
+}
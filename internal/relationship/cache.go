@@ -0,0 +1,82 @@
+package relationship
+
+import (
+	"sync"
+	"time"
+)
+
+type listKind int
+
+const (
+	kindFollowees listKind = iota
+	kindFollowers
+)
+
+type cacheKey struct {
+	kind   listKind
+	userID int64
+}
+
+// idSet is a scanned follower or followee list. complete is false when the scan stopped at maxScan.
+type idSet struct {
+	ids      map[int64]struct{}
+	complete bool
+}
+
+type cacheEntry struct {
+	set       idSet
+	expiresAt time.Time
+}
+
+// setCache keeps scanned lists for a short time so repeated status checks do not rescan.
+type setCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[cacheKey]cacheEntry
+}
+
+func newSetCache(ttl time.Duration) *setCache {
+	return &setCache{
+		ttl:     ttl,
+		entries: make(map[cacheKey]cacheEntry),
+	}
+}
+
+func (c *setCache) get(key cacheKey) (idSet, bool) {
+	if c.ttl <= 0 {
+		return idSet{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return idSet{}, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return idSet{}, false
+	}
+	return entry.set, true
+}
+
+func (c *setCache) put(key cacheKey, set idSet) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cacheEntry{set: set, expiresAt: now.Add(c.ttl)}
+}
+
+func (c *setCache) invalidate(userID int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, cacheKey{kind: kindFollowees, userID: userID})
+	delete(c.entries, cacheKey{kind: kindFollowers, userID: userID})
+}
@@ -0,0 +1,169 @@
+package relationship
+
+import (
+	"context"
+	relation_client "pinstack-api-gateway/internal/clients/relation"
+	"pinstack-api-gateway/internal/utils"
+	"time"
+)
+
+// scanPageSize is the page size used when walking follower and followee lists.
+const scanPageSize = 100
+
+const (
+	// maxFallbackTargets bounds how many targets of one request have their own lists scanned
+	// when the viewer's lists were cut short; the rest are reported as unknown.
+	maxFallbackTargets = 10
+	// fallbackConcurrency bounds how many of those targets are scanned at once.
+	fallbackConcurrency = 4
+)
+
+// Status describes how a viewer is connected to a target user.
+type Status struct {
+	Following  bool `json:"following"`
+	FollowedBy bool `json:"followed_by"`
+	Mutual     bool `json:"mutual"`
+	// Unknown is set when the relationship could not be confirmed within the scan limits,
+	// so a false Following or FollowedBy may be wrong.
+	Unknown bool `json:"unknown,omitempty"`
+}
+
+// Resolver answers relationship questions on top of the paged follower/followee RPCs,
+// since the relation service has no direct lookup. Each list is scanned up to maxScan entries
+// and kept for cacheTTL; when the viewer's list was cut short the target's opposite list is checked as well,
+// for at most maxFallbackTargets targets per call.
+type Resolver struct {
+	client  relation_client.RelationClient
+	maxScan int
+	cache   *setCache
+}
+
+func NewResolver(client relation_client.RelationClient, maxScan int, cacheTTL time.Duration) *Resolver {
+	if maxScan <= 0 {
+		maxScan = 1000
+	}
+	return &Resolver{
+		client:  client,
+		maxScan: maxScan,
+		cache:   newSetCache(cacheTTL),
+	}
+}
+
+// Status returns the relationship of viewerID to targetID.
+func (r *Resolver) Status(ctx context.Context, viewerID, targetID int64) (Status, error) {
+	statuses, err := r.StatusMany(ctx, viewerID, []int64{targetID})
+	if err != nil {
+		return Status{}, err
+	}
+	return statuses[targetID], nil
+}
+
+// StatusMany returns the relationship of viewerID to every target. The viewer has no relationship with themselves.
+// Targets beyond maxFallbackTargets that would need their own lists scanned are reported as Unknown.
+func (r *Resolver) StatusMany(ctx context.Context, viewerID int64, targetIDs []int64) (map[int64]Status, error) {
+	statuses := make(map[int64]Status, len(targetIDs))
+	followees, err := r.list(ctx, kindFollowees, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	followers, err := r.list(ctx, kindFollowers, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := make([]int64, 0)
+	for _, targetID := range targetIDs {
+		var status Status
+		if targetID == viewerID {
+			statuses[targetID] = status
+			continue
+		}
+
+		_, status.Following = followees.ids[targetID]
+		_, status.FollowedBy = followers.ids[targetID]
+		if (!status.Following && !followees.complete) || (!status.FollowedBy && !followers.complete) {
+			if len(fallback) < maxFallbackTargets {
+				fallback = append(fallback, targetID)
+			} else {
+				status.Unknown = true
+			}
+		}
+		status.Mutual = status.Following && status.FollowedBy
+		statuses[targetID] = status
+	}
+
+	resolved := make([]Status, len(fallback))
+	errs := utils.FanOut(ctx, len(fallback), fallbackConcurrency, func(ctx context.Context, i int) error {
+		targetID := fallback[i]
+		status := statuses[targetID]
+		var err error
+		if !status.Following && !followees.complete {
+			if status.Following, err = r.contains(ctx, kindFollowers, targetID, viewerID); err != nil {
+				return err
+			}
+		}
+		if !status.FollowedBy && !followers.complete {
+			if status.FollowedBy, err = r.contains(ctx, kindFollowees, targetID, viewerID); err != nil {
+				return err
+			}
+		}
+		status.Mutual = status.Following && status.FollowedBy
+		resolved[i] = status
+		return nil
+	})
+	for i, targetID := range fallback {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		statuses[targetID] = resolved[i]
+	}
+	return statuses, nil
+}
+
+// Invalidate drops cached lists of a user, e.g. after they follow or unfollow someone.
+func (r *Resolver) Invalidate(userIDs ...int64) {
+	for _, id := range userIDs {
+		r.cache.invalidate(id)
+	}
+}
+
+func (r *Resolver) contains(ctx context.Context, kind listKind, userID, id int64) (bool, error) {
+	set, err := r.list(ctx, kind, userID)
+	if err != nil {
+		return false, err
+	}
+	_, ok := set.ids[id]
+	return ok, nil
+}
+
+func (r *Resolver) list(ctx context.Context, kind listKind, userID int64) (idSet, error) {
+	key := cacheKey{kind: kind, userID: userID}
+	if set, ok := r.cache.get(key); ok {
+		return set, nil
+	}
+
+	set := idSet{ids: make(map[int64]struct{})}
+	for page := int32(1); ; page++ {
+		if len(set.ids) >= r.maxScan {
+			break
+		}
+		getPage := r.client.GetFollowees
+		if kind == kindFollowers {
+			getPage = r.client.GetFollowers
+		}
+		users, total, err := getPage(ctx, userID, scanPageSize, page)
+		if err != nil {
+			return idSet{}, err
+		}
+		for _, u := range users {
+			set.ids[u.ID] = struct{}{}
+		}
+		if len(users) < scanPageSize || int64(page)*scanPageSize >= total {
+			set.complete = true
+			break
+		}
+	}
+
+	r.cache.put(key, set)
+	return set, nil
+}
@@ -0,0 +1,192 @@
+package relationship
+
+import (
+	"context"
+	"math/rand/v2"
+	relation_client "pinstack-api-gateway/internal/clients/relation"
+	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/utils"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MaxSuggestions is how many ranked suggestions are computed and cached per user.
+const MaxSuggestions = 50
+
+// suggestionViaLimit is how many mutual connections are remembered per candidate for the explanation.
+const suggestionViaLimit = 3
+
+// Suggestion is a friend-of-friend candidate. Via holds the usernames of the viewer's followees who follow the candidate.
+type Suggestion struct {
+	UserID      int64
+	Username    string
+	AvatarURL   *string
+	MutualCount int
+	Via         []string
+}
+
+type SuggesterOptions struct {
+	SampleSize  int
+	Concurrency int
+	CacheTTL    time.Duration
+}
+
+// Suggester computes "people you may know" from a bounded sample of the viewer's followees' followees,
+// ranked by how many sampled followees follow each candidate.
+type Suggester struct {
+	client   relation_client.RelationClient
+	resolver *Resolver
+	opts     SuggesterOptions
+	mu       sync.Mutex
+	cache    map[int64]suggestionEntry
+}
+
+type suggestionEntry struct {
+	suggestions []Suggestion
+	expiresAt   time.Time
+}
+
+func NewSuggester(client relation_client.RelationClient, resolver *Resolver, opts SuggesterOptions) *Suggester {
+	if opts.SampleSize <= 0 {
+		opts.SampleSize = 50
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 8
+	}
+	return &Suggester{
+		client:   client,
+		resolver: resolver,
+		opts:     opts,
+		cache:    make(map[int64]suggestionEntry),
+	}
+}
+
+// Suggest returns up to MaxSuggestions ranked suggestions for userID.
+func (s *Suggester) Suggest(ctx context.Context, userID int64) ([]Suggestion, error) {
+	if suggestions, ok := s.cached(userID); ok {
+		return suggestions, nil
+	}
+
+	followees, following, err := s.sampleFollowees(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	lists := make([][]*models.RelationUser, len(followees))
+	errs := utils.FanOut(ctx, len(followees), s.opts.Concurrency, func(ctx context.Context, i int) error {
+		var err error
+		lists[i], _, err = s.client.GetFollowees(ctx, followees[i].ID, scanPageSize, 1)
+		return err
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	candidates := make(map[int64]*Suggestion)
+	failed := 0
+	for i, list := range lists {
+		if errs[i] != nil {
+			failed++
+			continue
+		}
+		for _, u := range list {
+			if u.ID == userID {
+				continue
+			}
+			if _, ok := following[u.ID]; ok {
+				continue
+			}
+			c, ok := candidates[u.ID]
+			if !ok {
+				c = &Suggestion{UserID: u.ID, Username: u.Username, AvatarURL: u.AvatarURL}
+				candidates[u.ID] = c
+			}
+			c.MutualCount++
+			if len(c.Via) < suggestionViaLimit {
+				c.Via = append(c.Via, followees[i].Username)
+			}
+		}
+	}
+	if failed > 0 && failed == len(followees) {
+		return nil, errs[0]
+	}
+
+	suggestions := make([]Suggestion, 0, len(candidates))
+	for _, c := range candidates {
+		suggestions = append(suggestions, *c)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].MutualCount != suggestions[j].MutualCount {
+			return suggestions[i].MutualCount > suggestions[j].MutualCount
+		}
+		return suggestions[i].UserID < suggestions[j].UserID
+	})
+	if len(suggestions) > MaxSuggestions {
+		suggestions = suggestions[:MaxSuggestions]
+	}
+
+	s.store(userID, suggestions)
+	return suggestions, nil
+}
+
+// Invalidate drops the cached suggestions of a user.
+func (s *Suggester) Invalidate(userID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.cache, userID)
+}
+
+// sampleFollowees reads up to the resolver's scan limit of followees and picks a random sample of SampleSize.
+// It also returns the IDs of every followee read, so candidates the user already follows can be skipped;
+// followees beyond the scan limit may still be suggested.
+func (s *Suggester) sampleFollowees(ctx context.Context, userID int64) ([]*models.RelationUser, map[int64]struct{}, error) {
+	followees := make([]*models.RelationUser, 0)
+	following := make(map[int64]struct{})
+	for page := int32(1); len(followees) < s.resolver.maxScan; page++ {
+		users, total, err := s.client.GetFollowees(ctx, userID, scanPageSize, page)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, u := range users {
+			followees = append(followees, u)
+			following[u.ID] = struct{}{}
+		}
+		if len(users) < scanPageSize || int64(page)*scanPageSize >= total {
+			break
+		}
+	}
+	if len(followees) > s.opts.SampleSize {
+		rand.Shuffle(len(followees), func(i, j int) { followees[i], followees[j] = followees[j], followees[i] })
+		followees = followees[:s.opts.SampleSize]
+	}
+	return followees, following, nil
+}
+
+func (s *Suggester) cached(userID int64) ([]Suggestion, bool) {
+	if s.opts.CacheTTL <= 0 {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.cache[userID]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.suggestions, true
+}
+
+func (s *Suggester) store(userID int64, suggestions []Suggestion) {
+	if s.opts.CacheTTL <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for id, entry := range s.cache {
+		if now.After(entry.expiresAt) {
+			delete(s.cache, id)
+		}
+	}
+	s.cache[userID] = suggestionEntry{suggestions: suggestions, expiresAt: now.Add(s.opts.CacheTTL)}
+}
@@ -0,0 +1,256 @@
+// Package selection implements sparse fieldsets (?fields=) and expansions (?expand=) for read endpoints.
+package selection
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+)
+
+var ErrInvalidSelection = errors.New("invalid fields or expand parameter")
+
+// maxFields bounds the number of field paths a request may select.
+const maxFields = 50
+
+// Selection is what a client asked an endpoint to include in its response.
+type Selection struct {
+	fields *tree
+	expand map[string]bool
+}
+
+// tree is a set of selected field paths. A nil subtree selects the whole field.
+type tree map[string]*tree
+
+// Parse reads the fields and expand parameters.
+//
+// fields is a comma separated list of JSON field names; nested fields are selected with dots,
+// as in author.username. Without it every field is returned. "id" is always returned.
+//
+// expand is a comma separated subset of expansions. Without the parameter, the endpoint's defaults
+// are expanded; an empty value expands nothing. Unknown expansions are rejected.
+func Parse(query url.Values, expansions []string, defaults []string) (*Selection, error) {
+	s := &Selection{expand: make(map[string]bool)}
+
+	if values, ok := query["expand"]; ok {
+		for _, value := range values {
+			for _, name := range strings.Split(value, ",") {
+				name = strings.ToLower(strings.TrimSpace(name))
+				if name == "" {
+					continue
+				}
+				if !slices.Contains(expansions, name) {
+					return nil, ErrInvalidSelection
+				}
+				s.expand[name] = true
+			}
+		}
+	} else {
+		for _, name := range defaults {
+			s.expand[name] = true
+		}
+	}
+
+	if values, ok := query["fields"]; ok {
+		s.fields = &tree{}
+		count := 0
+		for _, value := range values {
+			for _, path := range strings.Split(value, ",") {
+				path = strings.TrimSpace(path)
+				if path == "" {
+					continue
+				}
+				if count++; count > maxFields {
+					return nil, ErrInvalidSelection
+				}
+				if err := s.fields.add(strings.Split(path, ".")); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return s, nil
+}
+
+func (t *tree) add(path []string) error {
+	node := t
+	for i, name := range path {
+		if name == "" {
+			return ErrInvalidSelection
+		}
+		child, exists := (*node)[name]
+		if exists && child == nil {
+			// The whole field is already selected.
+			return nil
+		}
+		if i == len(path)-1 {
+			(*node)[name] = nil
+			return nil
+		}
+		if !exists {
+			child = &tree{}
+			(*node)[name] = child
+		}
+		node = child
+	}
+	return nil
+}
+
+// Expands reports whether the expansion name should be included. An expansion left out of an
+// explicit field selection is not needed either, so callers can skip the work behind it.
+func (s *Selection) Expands(name string) bool {
+	if !s.expand[name] {
+		return false
+	}
+	if s.fields == nil {
+		return true
+	}
+	_, ok := (*s.fields)[name]
+	return ok
+}
+
+// Requested reports whether the expansion name was asked for, regardless of the field selection.
+// It suits expansions that add several fields rather than one field named after the expansion.
+func (s *Selection) Requested(name string) bool {
+	return s.expand[name]
+}
+
+// HasFields reports whether the client selected fields, that is whether Apply trims the response.
+func (s *Selection) HasFields() bool {
+	return s.fields != nil
+}
+
+// Variant describes the selection for use in an ETag, so differently shaped responses get different tags.
+func (s *Selection) Variant() string {
+	var b strings.Builder
+	b.WriteString("expand=")
+	first := true
+	for _, name := range sortedKeys(s.expand) {
+		if !s.Expands(name) {
+			continue
+		}
+		if !first {
+			b.WriteByte(',')
+		}
+		first = false
+		b.WriteString(name)
+	}
+	if s.fields != nil {
+		b.WriteString(";fields=")
+		s.fields.write(&b)
+	}
+	return b.String()
+}
+
+// Apply trims data to the selected fields. With at, the selection applies to each element of the
+// collection found under that top-level key, and the rest of data, such as pagination metadata, is kept.
+// Data is returned unchanged when no fields were selected.
+func (s *Selection) Apply(data any, at ...string) (any, error) {
+	if s.fields == nil {
+		return data, nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	v = numbers(v)
+
+	if len(at) == 0 {
+		return s.fields.prune(v), nil
+	}
+	if m, ok := v.(map[string]any); ok {
+		for _, key := range at {
+			if items, ok := m[key]; ok {
+				m[key] = s.fields.prune(items)
+			}
+		}
+	}
+	return v, nil
+}
+
+func (t *tree) prune(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if key == "id" {
+				continue
+			}
+			sub, ok := (*t)[key]
+			switch {
+			case !ok:
+				delete(v, key)
+			case sub != nil:
+				v[key] = sub.prune(value)
+			}
+		}
+		return v
+	case []any:
+		for i := range v {
+			v[i] = t.prune(v[i])
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func (t *tree) write(b *strings.Builder) {
+	for i, name := range sortedKeys(*t) {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name)
+		if sub := (*t)[name]; sub != nil {
+			b.WriteByte('(')
+			sub.write(b)
+			b.WriteByte(')')
+		}
+	}
+}
+
+// numbers turns decoded json.Numbers back into integers or floats, so that binary codecs
+// encode them as numbers rather than strings.
+func numbers(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			v[key] = numbers(value)
+		}
+		return v
+	case []any:
+		for i := range v {
+			v[i] = numbers(v[i])
+		}
+		return v
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		if f, err := v.Float64(); err == nil && !math.IsInf(f, 0) {
+			return f
+		}
+		return v.String()
+	default:
+		return v
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,142 @@
+// Package userview serialises users for API responses, deciding per caller which fields they may see.
+package userview
+
+import (
+	"context"
+	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/user/v1"
+	"pinstack-api-gateway/internal/middlewares"
+	"pinstack-api-gateway/internal/models"
+	"pinstack-api-gateway/internal/utils"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// View is the set of user fields a caller is allowed to see.
+type View int
+
+const (
+	// Public is what anyone, signed in or not, sees of another user.
+	Public View = iota
+	// Self is what users see of their own account. It adds the email.
+	Self
+	// Admin is what administrators see of any account. It adds the email.
+	Admin
+)
+
+func (v View) String() string {
+	switch v {
+	case Self:
+		return "self"
+	case Admin:
+		return "admin"
+	default:
+		return "public"
+	}
+}
+
+// showsEmail reports whether the view includes private contact details.
+func (v View) showsEmail() bool {
+	return v == Self || v == Admin
+}
+
+// Policy picks the view of a user for the caller of a request.
+type Policy struct {
+	admins map[int64]struct{}
+}
+
+func NewPolicy(adminIDs []int64) *Policy {
+	admins := make(map[int64]struct{}, len(adminIDs))
+	for _, id := range adminIDs {
+		admins[id] = struct{}{}
+	}
+	return &Policy{admins: admins}
+}
+
+// IsAdmin reports whether userID is configured as an administrator.
+func (p *Policy) IsAdmin(userID int64) bool {
+	_, ok := p.admins[userID]
+	return ok
+}
+
+// For returns the view of the user with the given ID for the caller authenticated on ctx.
+// Anonymous callers always get the public view.
+func (p *Policy) For(ctx context.Context, userID int64) View {
+	claims, err := middlewares.GetClaimsFromContext(ctx)
+	if err != nil {
+		return Public
+	}
+	switch {
+	case p.IsAdmin(claims.UserID):
+		return Admin
+	case claims.UserID == userID:
+		return Self
+	default:
+		return Public
+	}
+}
+
+// User is a user as returned by the users API.
+type User struct {
+	ID             int64                 `json:"id"`
+	Username       string                `json:"username"`
+	Email          *string               `json:"email,omitempty"`
+	FullName       *string               `json:"full_name,omitempty"`
+	Bio            *string               `json:"bio,omitempty"`
+	AvatarURL      *string               `json:"avatar_url,omitempty"`
+	AvatarVariants *models.MediaVariants `json:"avatar_variants,omitempty"`
+	CreatedAt      string                `json:"created_at"`
+	UpdatedAt      string                `json:"updated_at"`
+}
+
+// NewUser serialises u for view. The avatar is left for the caller to resolve, since handlers
+// differ in how they link to it.
+func NewUser(u *models.User, view View) User {
+	user := User{
+		ID:        u.ID,
+		Username:  u.Username,
+		FullName:  u.FullName,
+		Bio:       u.Bio,
+		CreatedAt: u.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt: u.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if view.showsEmail() {
+		email := u.Email
+		user.Email = &email
+	}
+	return user
+}
+
+// Author is the public summary of a user embedded in posts, feeds and other resources.
+// It is the same for every caller.
+type Author struct {
+	ID        int64   `json:"id"`
+	Username  string  `json:"username"`
+	FullName  *string `json:"full_name,omitempty"`
+	AvatarURL *string `json:"avatar_url,omitempty"`
+}
+
+// NewAuthor summarises u, linking to the already resolved avatarURL.
+func NewAuthor(u *models.User, avatarURL *string) *Author {
+	return &Author{
+		ID:        u.ID,
+		Username:  u.Username,
+		FullName:  u.FullName,
+		AvatarURL: avatarURL,
+	}
+}
+
+func (u User) ToProto() proto.Message {
+	msg := &pb.User{
+		Id:        u.ID,
+		Username:  u.Username,
+		FullName:  u.FullName,
+		Bio:       u.Bio,
+		AvatarUrl: u.AvatarURL,
+		CreatedAt: utils.ProtoTimestamp(u.CreatedAt),
+		UpdatedAt: utils.ProtoTimestamp(u.UpdatedAt),
+	}
+	if u.Email != nil {
+		msg.Email = *u.Email
+	}
+	return msg
+}
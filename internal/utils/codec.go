@@ -0,0 +1,227 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	MediaTypeJSON     = "application/json"
+	MediaTypeMsgPack  = "application/msgpack"
+	MediaTypeProtobuf = "application/x-protobuf"
+)
+
+// ErrNotRepresentable is returned by a codec that has no encoding for a response payload.
+var ErrNotRepresentable = errors.New("response cannot be represented in the requested media type")
+
+// Codec encodes response envelopes into a specific media type.
+type Codec interface {
+	ContentType() string
+	Encode(w io.Writer, response Response) error
+}
+
+// ProtoConvertible is implemented by response payloads that have a message type in
+// pinstack-proto-definitions, making them available as application/x-protobuf.
+type ProtoConvertible interface {
+	ToProto() proto.Message
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) ContentType() string { return MediaTypeJSON }
+
+func (jsonCodec) Encode(w io.Writer, response Response) error {
+	return json.NewEncoder(w).Encode(response)
+}
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) ContentType() string { return MediaTypeMsgPack }
+
+func (msgpackCodec) Encode(w io.Writer, response Response) error {
+	enc := msgpack.NewEncoder(w)
+	enc.SetCustomStructTag("json")
+	return enc.Encode(response)
+}
+
+// protobufCodec writes the envelope as the message
+//
+//	message Response {
+//	  int32 status = 1;
+//	  string message = 2;
+//	  <payload type> data = 3;
+//	}
+//
+// where the payload type is the pinstack-proto-definitions message the endpoint documents.
+// Payloads without a proto counterpart are rejected with ErrNotRepresentable.
+type protobufCodec struct{}
+
+func (protobufCodec) ContentType() string { return MediaTypeProtobuf }
+
+func (protobufCodec) Encode(w io.Writer, response Response) error {
+	var body []byte
+	if response.Status != 0 {
+		body = protowire.AppendTag(body, 1, protowire.VarintType)
+		body = protowire.AppendVarint(body, uint64(int32(response.Status)))
+	}
+	if response.Message != "" {
+		body = protowire.AppendTag(body, 2, protowire.BytesType)
+		body = protowire.AppendString(body, response.Message)
+	}
+	if response.Data != nil {
+		convertible, ok := response.Data.(ProtoConvertible)
+		if !ok {
+			return ErrNotRepresentable
+		}
+		data, err := proto.Marshal(convertible.ToProto())
+		if err != nil {
+			return err
+		}
+		body = protowire.AppendTag(body, 3, protowire.BytesType)
+		body = protowire.AppendBytes(body, data)
+	}
+	_, err := w.Write(body)
+	return err
+}
+
+var (
+	JSONCodec     Codec = jsonCodec{}
+	MsgPackCodec  Codec = msgpackCodec{}
+	ProtobufCodec Codec = protobufCodec{}
+)
+
+var codecsByMediaType = map[string]Codec{
+	MediaTypeJSON:                     JSONCodec,
+	MediaTypeMsgPack:                  MsgPackCodec,
+	"application/x-msgpack":           MsgPackCodec,
+	MediaTypeProtobuf:                 ProtobufCodec,
+	"application/protobuf":            ProtobufCodec,
+	"application/vnd.google.protobuf": ProtobufCodec,
+}
+
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// NegotiateCodec picks a codec for the given Accept header value.
+// It returns false when none of the supported media types is acceptable.
+func NegotiateCodec(accept string) (Codec, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return JSONCodec, true
+	}
+
+	ranges := make([]acceptRange, 0, 4)
+	for _, part := range strings.Split(accept, ",") {
+		fields := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(fields[0]))
+		if mediaType == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if !found || strings.ToLower(strings.TrimSpace(key)) != "q" {
+				continue
+			}
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = parsed
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, q: q})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].q != ranges[j].q {
+			return ranges[i].q > ranges[j].q
+		}
+		return specificity(ranges[i].mediaType) > specificity(ranges[j].mediaType)
+	})
+
+	for _, ar := range ranges {
+		if ar.q <= 0 {
+			continue
+		}
+		switch ar.mediaType {
+		case "*/*", "application/*":
+			return JSONCodec, true
+		}
+		if codec, ok := codecsByMediaType[ar.mediaType]; ok {
+			return codec, true
+		}
+	}
+	return nil, false
+}
+
+func specificity(mediaType string) int {
+	switch {
+	case mediaType == "*/*":
+		return 0
+	case strings.HasSuffix(mediaType, "/*"):
+		return 1
+	default:
+		return 2
+	}
+}
+
+type codecCarrier interface {
+	Codec() Codec
+}
+
+type codecResponseWriter struct {
+	http.ResponseWriter
+	codec Codec
+}
+
+// NewCodecResponseWriter binds a negotiated codec to the response writer so Send and SendError can use it.
+func NewCodecResponseWriter(w http.ResponseWriter, codec Codec) http.ResponseWriter {
+	return &codecResponseWriter{ResponseWriter: w, codec: codec}
+}
+
+func (w *codecResponseWriter) Codec() Codec {
+	return w.codec
+}
+
+func (w *codecResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *codecResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func codecFromWriter(w http.ResponseWriter) Codec {
+	for w != nil {
+		if carrier, ok := w.(codecCarrier); ok {
+			return carrier.Codec()
+		}
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			break
+		}
+		w = unwrapper.Unwrap()
+	}
+	return JSONCodec
+}
+
+// ProtoTimestamp converts a timestamp formatted for a JSON response back into a proto timestamp.
+func ProtoTimestamp(formatted string) *timestamppb.Timestamp {
+	t, err := time.Parse(time.RFC3339, formatted)
+	if err != nil {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ResourceETag builds a strong ETag from the parts that identify a resource version.
+func ResourceETag(parts ...string) string {
+	return `"` + digest(parts) + `"`
+}
+
+// RepresentationETag builds a strong ETag for one representation of a resource version, such as
+// a particular codec or field selection. The tag has the form "<version>.<variant>", so If-Match
+// can compare versions regardless of which representation the client was sent.
+func RepresentationETag(version []string, variants ...string) string {
+	if len(variants) == 0 {
+		return ResourceETag(version...)
+	}
+	return `"` + digest(version) + "." + digest(variants)[:16] + `"`
+}
+
+// CodecVariant names the negotiated codec as an ETag variant, since each codec produces different bytes.
+func CodecVariant(w http.ResponseWriter) string {
+	return codecFromWriter(w).ContentType()
+}
+
+func digest(parts []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// VersionTag formats a timestamp for use as an ETag part.
+func VersionTag(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// SetValidators writes ETag and Last-Modified headers.
+func SetValidators(w http.ResponseWriter, etag string, lastModified time.Time) {
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+}
+
+// CheckNotModified sets the validators and answers 304 when the client copy is still fresh.
+// It returns true if the response has already been written.
+func CheckNotModified(w http.ResponseWriter, r *http.Request, etag string, lastModified time.Time) bool {
+	SetValidators(w, etag, lastModified)
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		if !etagListMatches(inm, etag) {
+			return false
+		}
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(ims)
+		if err != nil || lastModified.Truncate(time.Second).After(t) {
+			return false
+		}
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+
+	return false
+}
+
+// PreconditionFailed reports whether an If-Match header is present and names a version other than
+// the one etag identifies. Only the version part of representation tags is compared, since a client
+// may edit from any representation of the current version.
+func PreconditionFailed(r *http.Request, etag string) bool {
+	im := r.Header.Get("If-Match")
+	if im == "" {
+		return false
+	}
+	if strings.TrimSpace(im) == "*" {
+		return etag == ""
+	}
+	current := etagVersion(etag)
+	for _, candidate := range strings.Split(im, ",") {
+		if etagVersion(candidate) == current {
+			return false
+		}
+	}
+	return true
+}
+
+// etagVersion strips the weak prefix, quotes and representation variant from an ETag.
+func etagVersion(etag string) string {
+	etag = strings.Trim(strings.TrimPrefix(strings.TrimSpace(etag), "W/"), `"`)
+	version, _, _ := strings.Cut(etag, ".")
+	return version
+}
+
+// etagListMatches compares an If-Match / If-None-Match header against the current ETag.
+// The W/ prefix is ignored on both sides: the gateway only weakens its own strong tags
+// when the compression middleware re-encodes the body, so the opaque tag still identifies the version.
+func etagListMatches(header, etag string) bool {
+	if strings.TrimSpace(header) == "*" {
+		return etag != ""
+	}
+	current := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == current {
+			return true
+		}
+	}
+	return false
+}
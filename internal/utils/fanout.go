@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"context"
+	"sync"
+)
+
+// FanOut runs fn for indexes [0, n) with at most limit calls in flight and returns each call's error by index.
+// Once ctx is done no further calls are started and the remaining entries report ctx.Err().
+func FanOut(ctx context.Context, n, limit int, fn func(ctx context.Context, i int) error) []error {
+	if limit <= 0 {
+		limit = 1
+	}
+	errs := make([]error, n)
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			for j := i; j < n; j++ {
+				errs[j] = ctx.Err()
+			}
+			wg.Wait()
+			return errs
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = fn(ctx, i)
+		}(i)
+	}
+	wg.Wait()
+	return errs
+}
@@ -1,8 +1,10 @@
 package utils
 
 import (
-	"encoding/json"
+	"bytes"
+	"errors"
 	"net/http"
+	"pinstack-api-gateway/internal/selection"
 )
 
 type Response struct {
@@ -12,33 +14,50 @@ type Response struct {
 }
 
 func Send(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-
-	response := Response{
+	write(w, Response{
 		Status: status,
 		Data:   data,
-	}
-
-	err := json.NewEncoder(w).Encode(response)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
+	})
 }
 
 func SendError(w http.ResponseWriter, status int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-
-	response := Response{
+	write(w, Response{
 		Status:  status,
 		Message: message,
+	})
+}
+
+// write encodes the envelope before sending any headers, so a payload the negotiated codec
+// cannot represent is answered with 406 instead.
+func write(w http.ResponseWriter, response Response) {
+	codec := codecFromWriter(w)
+	var body bytes.Buffer
+	if err := codec.Encode(&body, response); err != nil {
+		if errors.Is(err, ErrNotRepresentable) {
+			SendError(w, http.StatusNotAcceptable, "not acceptable")
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
 	}
 
-	err := json.NewEncoder(w).Encode(response)
+	w.Header().Set("Content-Type", codec.ContentType())
+	w.WriteHeader(response.Status)
+	_, _ = w.Write(body.Bytes())
+}
+
+// SendSelected is Send with the response trimmed to the fields the client selected.
+// With at, the selection applies to the elements of those top-level collections.
+// Protobuf messages have a fixed shape, so a field selection is refused with 406 for that media type.
+func SendSelected(w http.ResponseWriter, status int, sel *selection.Selection, data interface{}, at ...string) {
+	if sel.HasFields() && codecFromWriter(w) == ProtobufCodec {
+		SendError(w, http.StatusNotAcceptable, "fields cannot be combined with "+MediaTypeProtobuf)
+		return
+	}
+	trimmed, err := sel.Apply(data, at...)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		SendError(w, http.StatusInternalServerError, "failed to encode response")
 		return
 	}
+	Send(w, status, trimmed)
 }
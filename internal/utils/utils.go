@@ -17,11 +17,10 @@ func StringPtr(s string) *string {
 
 func GenerateUnknownAuthor() *models.User {
 	return &models.User{
-		ID:        0,
-		Username:  "unknown",
-		FullName:  StringPtr("Unknown Author"),
-		AvatarURL: StringPtr("http://unknown.unknown"),
-		Email:     "unknown@unknown.com",
-		Bio:       StringPtr("Unknown Author BIO"),
+		ID:       0,
+		Username: "unknown",
+		FullName: StringPtr("Unknown Author"),
+		Email:    "unknown@unknown.com",
+		Bio:      StringPtr("Unknown Author BIO"),
 	}
 }